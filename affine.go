@@ -0,0 +1,164 @@
+package mfcg
+
+import "math"
+
+// Affine is a 2D affine transformation mapping a Point (x, y) to
+// (A*x + B*y + C, D*x + E*y + F). The zero Affine maps every Point to the
+// origin; use Identity as a starting point instead.
+type Affine struct {
+	A, B, C float64
+	D, E, F float64
+}
+
+// Identity returns the Affine leaving every Point unchanged.
+func Identity() Affine {
+	return Affine{A: 1, E: 1}
+}
+
+// Translate returns the Affine moving every Point by dx and dy.
+func Translate(dx, dy float64) Affine {
+	return Affine{A: 1, C: dx, E: 1, F: dy}
+}
+
+// Scale returns the Affine scaling every Point by sx and sy about the origin.
+func Scale(sx, sy float64) Affine {
+	return Affine{A: sx, E: sy}
+}
+
+// Rotate returns the Affine rotating every Point about the origin by the
+// provided angle in radians. Positive angles rotate counterclockwise on a
+// y-up plane, which appears clockwise in MFCG's y-down coordinates.
+func Rotate(angle float64) Affine {
+	sin, cos := math.Sincos(angle)
+	return Affine{A: cos, B: -sin, D: sin, E: cos}
+}
+
+// FlipY returns the Affine mirroring every Point across the x-axis, turning
+// MFCG's y-down coordinates into y-up coordinates and back.
+func FlipY() Affine {
+	return Affine{A: 1, E: -1}
+}
+
+// Mul returns the Affine applying b and then a.
+func (a Affine) Mul(b Affine) Affine {
+	return Affine{
+		A: a.A*b.A + a.B*b.D,
+		B: a.A*b.B + a.B*b.E,
+		C: a.A*b.C + a.B*b.F + a.C,
+		D: a.D*b.A + a.E*b.D,
+		E: a.D*b.B + a.E*b.E,
+		F: a.D*b.C + a.E*b.F + a.F,
+	}
+}
+
+// Apply returns Point pt transformed by the Affine.
+func (a Affine) Apply(pt Point) Point {
+	return Point{
+		X: a.A*pt.X + a.B*pt.Y + a.C,
+		Y: a.D*pt.X + a.E*pt.Y + a.F,
+	}
+}
+
+// scale returns the factor by which the Affine scales lengths on average.
+func (a Affine) scale() float64 {
+	return math.Sqrt(math.Abs(a.A*a.E - a.B*a.D))
+}
+
+// Transform applies the provided Affine to every Point of the Map in place,
+// including its Districts and Extra features, except for Extra features kept
+// as Raw JSON. Widths and the MetaData's road width, river width, wall
+// thickness and tower radius are scaled by the Affine's average scale
+// factor, the road width being rounded to the nearest integer. Transforms
+// which mirror the Map also reverse the winding of its rings.
+func (m *Map) Transform(a Affine) {
+	m.mapPoints(a.Apply)
+	m.scaleWidths(a.scale())
+}
+
+// scaleWidths multiplies every width of the Map and the MetaData's road
+// width, river width, wall thickness and tower radius by k. The road width
+// is rounded to the nearest integer.
+func (m *Map) scaleWidths(k float64) {
+	m.RoadWidth = int(math.Round(float64(m.RoadWidth) * k))
+	m.RiverWidth *= k
+	m.WallThickness *= k
+	m.TowerRadius *= k
+
+	for _, lines := range [][]LineString{m.Planks, m.Rivers, m.Roads} {
+		for i := range lines {
+			lines[i].Width *= k
+		}
+	}
+	m.Earth.Width *= k
+	for _, polys := range [][]Polygon{m.Buildings, m.Fields, m.Greens, m.Prisms, m.Squares, m.Walls, m.Water} {
+		for i := range polys {
+			polys[i].Width *= k
+		}
+	}
+	for i := range m.Districts {
+		m.Districts[i].Width *= k
+	}
+}
+
+// mapPoints replaces every Point of the Map in place with the result of
+// calling fn on it.
+func (m *Map) mapPoints(fn func(Point) Point) {
+	for _, l := range m.polygonLayers() {
+		for _, p := range l.polys {
+			mapRings(p.Coords, fn)
+		}
+	}
+
+	for _, d := range m.Districts {
+		mapRings(d.Coords, fn)
+	}
+
+	for _, l := range m.lineLayers() {
+		for _, ln := range l.lines {
+			mapRing(ln.Coords, fn)
+		}
+	}
+
+	mapRing(m.Trees, fn)
+
+	for i := range m.Extra {
+		mapGeometry(&m.Extra[i].Geometry, fn)
+	}
+}
+
+// mapGeometry replaces every Point of Geometry g in place with the result of
+// calling fn on it.
+func mapGeometry(g *Geometry, fn func(Point) Point) {
+	switch c := g.Coordinates.(type) {
+	case Point:
+		g.Coordinates = fn(c)
+	case []Point:
+		mapRing(c, fn)
+	case [][]Point:
+		mapRings(c, fn)
+	case [][][]Point:
+		for _, rings := range c {
+			mapRings(rings, fn)
+		}
+	}
+
+	for i := range g.Geometries {
+		mapGeometry(&g.Geometries[i], fn)
+	}
+}
+
+// mapRings replaces every Point of the provided rings in place with the
+// result of calling fn on it.
+func mapRings(rings [][]Point, fn func(Point) Point) {
+	for _, ring := range rings {
+		mapRing(ring, fn)
+	}
+}
+
+// mapRing replaces every Point of pts in place with the result of calling fn
+// on it.
+func mapRing(pts []Point, fn func(Point) Point) {
+	for i, pt := range pts {
+		pts[i] = fn(pt)
+	}
+}
@@ -0,0 +1,75 @@
+package mfcg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestAffine_Apply(t *testing.T) {
+	tests := []struct {
+		name string
+		a    Affine
+		pt   Point
+		want Point
+	}{
+		{"Identity", Identity(), Point{X: 3, Y: -4}, Point{X: 3, Y: -4}},
+		{"Translate", Translate(1, 2), Point{X: 3, Y: -4}, Point{X: 4, Y: -2}},
+		{"Scale", Scale(2, 3), Point{X: 3, Y: -4}, Point{X: 6, Y: -12}},
+		{"Rotate", Rotate(math.Pi / 2), Point{X: 1, Y: 0}, Point{X: 0, Y: 1}},
+		{"FlipY", FlipY(), Point{X: 3, Y: -4}, Point{X: 3, Y: 4}},
+		{"Scale then translate", Translate(1, 2).Mul(Scale(2, 2)), Point{X: 3, Y: -4}, Point{X: 7, Y: -6}},
+		{"Translate then scale", Scale(2, 2).Mul(Translate(1, 2)), Point{X: 3, Y: -4}, Point{X: 8, Y: -4}},
+		{"Rotate then flip", FlipY().Mul(Rotate(math.Pi / 2)), Point{X: 1, Y: 0}, Point{X: 0, Y: -1}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := test.a.Apply(test.pt)
+			if diff := cmp.Diff(got, test.want, cmpopts.EquateApprox(0, 1e-12)); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMap_Transform(t *testing.T) {
+	mp := &Map{
+		MetaData:  MetaData{RoadWidth: 8, RiverWidth: 20, WallThickness: 7.6, TowerRadius: 7.6},
+		Earth:     Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}}},
+		Roads:     []LineString{{Width: 8, Coords: []Point{{X: 1, Y: 2}, {X: 3, Y: 4}}}},
+		Walls:     []Polygon{{Width: 7.6, Coords: [][]Point{{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}}}}},
+		Trees:     []Point{{X: 5, Y: 5}},
+		Districts: []District{{Name: "Docks", Polygon: Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}}}}}},
+		Extra: []Feature{
+			{ID: "well", Geometry: Geometry{Type: "Point", Coordinates: Point{X: 1, Y: 1}}},
+			{ID: "banners", Geometry: Geometry{Type: "GeometryCollection", Geometries: []Geometry{
+				{Type: "LineString", Coordinates: []Point{{X: 0, Y: 1}, {X: 1, Y: 0}}},
+			}}},
+		},
+	}
+
+	want := &Map{
+		MetaData:  MetaData{RoadWidth: 20, RiverWidth: 50, WallThickness: 19, TowerRadius: 19},
+		Earth:     Polygon{Coords: [][]Point{{{X: 1, Y: 0}, {X: 26, Y: 0}, {X: 26, Y: -25}}}},
+		Roads:     []LineString{{Width: 20, Coords: []Point{{X: 3.5, Y: -5}, {X: 8.5, Y: -10}}}},
+		Walls:     []Polygon{{Width: 19, Coords: [][]Point{{{X: 1, Y: 0}, {X: 3.5, Y: 0}, {X: 3.5, Y: -2.5}}}}},
+		Trees:     []Point{{X: 13.5, Y: -12.5}},
+		Districts: []District{{Name: "Docks", Polygon: Polygon{Coords: [][]Point{{{X: 1, Y: 0}, {X: 6, Y: 0}, {X: 6, Y: -5}}}}}},
+		Extra: []Feature{
+			{ID: "well", Geometry: Geometry{Type: "Point", Coordinates: Point{X: 3.5, Y: -2.5}}},
+			{ID: "banners", Geometry: Geometry{Type: "GeometryCollection", Geometries: []Geometry{
+				{Type: "LineString", Coordinates: []Point{{X: 1, Y: -2.5}, {X: 3.5, Y: 0}}},
+			}}},
+		},
+	}
+
+	mp.Transform(Translate(1, 0).Mul(FlipY()).Mul(Scale(2.5, 2.5)))
+
+	if diff := cmp.Diff(mp, want, cmpopts.EquateApprox(0, 1e-12)); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
@@ -0,0 +1,228 @@
+package mfcg
+
+import "math"
+
+// JoinStyle describes how Buffer joins the segments of a LineString.
+type JoinStyle int
+
+// Join styles supported by Buffer.
+const (
+	JoinRound JoinStyle = iota // JoinRound joins segments with an arc.
+	JoinMiter                  // JoinMiter extends the segments' edges until they meet.
+	JoinBevel                  // JoinBevel cuts the corner between the segments' edges.
+)
+
+// CapStyle describes how Buffer ends a LineString.
+type CapStyle int
+
+// Cap styles supported by Buffer.
+const (
+	CapRound  CapStyle = iota // CapRound ends the line with a half circle.
+	CapButt                   // CapButt ends the line flat at its endpoint.
+	CapSquare                 // CapSquare ends the line flat half its width past its endpoint.
+)
+
+// Defaults used by Buffer when BufferOptions leaves a field unset.
+const (
+	defaultMiterLimit  = 4
+	defaultArcSegments = 8
+)
+
+// BufferOptions configures the shape of a buffered LineString. The zero
+// BufferOptions produces round joins and caps.
+type BufferOptions struct {
+	Join        JoinStyle // Join is the style of the joins between segments.
+	Cap         CapStyle  // Cap is the style of both ends of the line.
+	MiterLimit  float64   // MiterLimit is the longest miter allowed, relative to the line's width, before a join is beveled. Defaults to 4.
+	ArcSegments int       // ArcSegments is the number of segments approximating a quarter circle. Defaults to 8.
+}
+
+// Buffer returns the area covered by the LineString when drawn with its
+// Width, as Polygons wound counterclockwise on a y-up plane. The pieces of a
+// line overlap one another, so a single Polygon is usually returned, but
+// rounding may split off slivers, which are returned as further Polygons. A
+// LineString crossing itself may enclose holes. Nil is returned if the
+// LineString has no width or no Points.
+func (ln LineString) Buffer(opts BufferOptions) []Polygon {
+	return overlay(ln.bufferPieces(opts), nil, opUnion)
+}
+
+// RoadSurfaces returns the area covered by the Map's roads, each buffered
+// with its width using round joins and caps, merged into Polygons.
+func (m *Map) RoadSurfaces() []Polygon {
+	return bufferLines(m.Roads)
+}
+
+// RiverSurfaces returns the area covered by the Map's rivers, each buffered
+// with its width using round joins and caps, merged into Polygons.
+func (m *Map) RiverSurfaces() []Polygon {
+	return bufferLines(m.Rivers)
+}
+
+// bufferLines returns the union of the provided LineStrings buffered with
+// the default BufferOptions.
+func bufferLines(lines []LineString) []Polygon {
+	var pieces []Polygon
+	for _, ln := range lines {
+		pieces = append(pieces, ln.bufferPieces(BufferOptions{})...)
+	}
+
+	return overlay(pieces, nil, opUnion)
+}
+
+// bufferPieces returns overlapping Polygons whose union is the LineString's
+// buffer: one quad per segment, one piece per join and one per cap.
+func (ln LineString) bufferPieces(opts BufferOptions) []Polygon {
+	half := ln.Width / 2
+	pts, _ := removeDuplicates(append([]Point(nil), ln.Coords...))
+	if half <= 0 || len(pts) == 0 {
+		return nil
+	}
+
+	if opts.MiterLimit <= 0 {
+		opts.MiterLimit = defaultMiterLimit
+	}
+	if opts.ArcSegments <= 0 {
+		opts.ArcSegments = defaultArcSegments
+	}
+	step := math.Pi / 2 / float64(opts.ArcSegments)
+
+	piece := func(ring ...Point) Polygon {
+		return Polygon{Coords: [][]Point{ring}}
+	}
+
+	if len(pts) == 1 {
+		switch opts.Cap {
+		case CapRound:
+			return []Polygon{piece(arc(pts[0], half, 0, 2*math.Pi, step)...)}
+		case CapSquare:
+			c := pts[0]
+			return []Polygon{piece(
+				Point{X: c.X - half, Y: c.Y - half},
+				Point{X: c.X + half, Y: c.Y - half},
+				Point{X: c.X + half, Y: c.Y + half},
+				Point{X: c.X - half, Y: c.Y + half},
+			)}
+		}
+		return nil
+	}
+
+	var pieces []Polygon
+	for i := 1; i < len(pts); i++ {
+		a, b := pts[i-1], pts[i]
+		n := leftNormal(a, b, half)
+		pieces = append(pieces, piece(
+			Point{X: a.X - n.X, Y: a.Y - n.Y},
+			Point{X: b.X - n.X, Y: b.Y - n.Y},
+			Point{X: b.X + n.X, Y: b.Y + n.Y},
+			Point{X: a.X + n.X, Y: a.Y + n.Y},
+		))
+	}
+
+	for i := 1; i < len(pts)-1; i++ {
+		if j := join(pts[i-1], pts[i], pts[i+1], half, opts, step); j != nil {
+			pieces = append(pieces, piece(j...))
+		}
+	}
+
+	ends := [][2]Point{{pts[1], pts[0]}, {pts[len(pts)-2], pts[len(pts)-1]}}
+	for _, end := range ends {
+		from, at := end[0], end[1]
+		n := leftNormal(from, at, half)
+		switch opts.Cap {
+		case CapRound:
+			start := math.Atan2(-n.Y, -n.X)
+			pieces = append(pieces, piece(arc(at, half, start, math.Pi, step)...))
+		case CapSquare:
+			d := Point{X: n.Y, Y: -n.X}
+			pieces = append(pieces, piece(
+				Point{X: at.X - n.X, Y: at.Y - n.Y},
+				Point{X: at.X - n.X + d.X, Y: at.Y - n.Y + d.Y},
+				Point{X: at.X + n.X + d.X, Y: at.Y + n.Y + d.Y},
+				Point{X: at.X + n.X, Y: at.Y + n.Y},
+			))
+		}
+	}
+
+	return pieces
+}
+
+// join returns the ring filling the outer corner at Point b between the
+// segments from a to b and from b to c, or nil if the segments are
+// collinear.
+func join(a, b, c Point, half float64, opts BufferOptions, step float64) []Point {
+	turn := orient(a, b, c)
+	if turn == 0 {
+		return nil
+	}
+
+	// The outer corner lies to the right of a left turn and to the left of a
+	// right turn.
+	n0, n1 := leftNormal(a, b, half), leftNormal(b, c, half)
+	if turn > 0 {
+		n0, n1 = Point{X: -n0.X, Y: -n0.Y}, Point{X: -n1.X, Y: -n1.Y}
+	}
+	p0 := Point{X: b.X + n0.X, Y: b.Y + n0.Y}
+	p1 := Point{X: b.X + n1.X, Y: b.Y + n1.Y}
+
+	switch opts.Join {
+	case JoinMiter:
+		sum := Point{X: n0.X + n1.X, Y: n0.Y + n1.Y}
+		norm := math.Hypot(sum.X, sum.Y)
+		// The miter's length relative to the line's width is the secant of
+		// half the angle between the segments.
+		ratio := 2 * half / norm
+		if norm > 0 && ratio <= opts.MiterLimit {
+			k := 2 * half * half / (norm * norm)
+			tip := Point{X: b.X + sum.X*k, Y: b.Y + sum.Y*k}
+			return []Point{b, p0, tip, p1}
+		}
+		return []Point{b, p0, p1}
+	case JoinBevel:
+		return []Point{b, p0, p1}
+	}
+
+	start := math.Atan2(n0.Y, n0.X)
+	sweep := math.Atan2(n1.Y, n1.X) - start
+	for sweep > math.Pi {
+		sweep -= 2 * math.Pi
+	}
+	for sweep < -math.Pi {
+		sweep += 2 * math.Pi
+	}
+
+	return append([]Point{b}, arc(b, half, start, sweep, step)...)
+}
+
+// arc returns the Points of the arc of the provided radius around c starting
+// at angle start and turning by sweep, in radians, with segments turning by
+// at most step. Both ends of the arc are included, unless the arc is a full
+// circle.
+func arc(c Point, radius, start, sweep, step float64) []Point {
+	n := int(math.Ceil(math.Abs(sweep) / step))
+	if n < 1 {
+		n = 1
+	}
+
+	full := math.Abs(sweep) >= 2*math.Pi
+	pts := make([]Point, 0, n+1)
+	for i := 0; i <= n; i++ {
+		if full && i == n {
+			break
+		}
+
+		theta := start + sweep*float64(i)/float64(n)
+		pts = append(pts, Point{X: c.X + radius*math.Cos(theta), Y: c.Y + radius*math.Sin(theta)})
+	}
+
+	return pts
+}
+
+// leftNormal returns the vector of the provided length perpendicular to the
+// segment from a to b, pointing to its left on a y-up plane.
+func leftNormal(a, b Point, length float64) Point {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	d := math.Hypot(dx, dy)
+
+	return Point{X: -dy / d * length, Y: dx / d * length}
+}
@@ -0,0 +1,159 @@
+package mfcg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestLineString_Buffer(t *testing.T) {
+	straight := LineString{Width: 2, Coords: []Point{{X: 0, Y: 0}, {X: 10, Y: 0}}}
+	bend := LineString{Width: 2, Coords: []Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}}
+
+	tests := []struct {
+		name     string
+		ln       LineString
+		opts     BufferOptions
+		want     []Polygon
+		wantArea float64
+	}{
+		{
+			name: "Butt caps",
+			ln:   straight,
+			opts: BufferOptions{Cap: CapButt},
+			want: []Polygon{{Coords: [][]Point{
+				{{X: 0, Y: -1}, {X: 10, Y: -1}, {X: 10, Y: 1}, {X: 0, Y: 1}},
+			}}},
+			wantArea: 20,
+		},
+		{
+			name: "Square caps",
+			ln:   straight,
+			opts: BufferOptions{Cap: CapSquare},
+			want: []Polygon{{Coords: [][]Point{
+				{{X: -1, Y: -1}, {X: 11, Y: -1}, {X: 11, Y: 1}, {X: -1, Y: 1}},
+			}}},
+			wantArea: 24,
+		},
+		{
+			name: "Miter join",
+			ln:   bend,
+			opts: BufferOptions{Cap: CapButt, Join: JoinMiter},
+			want: []Polygon{{Coords: [][]Point{
+				{{X: 0, Y: -1}, {X: 11, Y: -1}, {X: 11, Y: 10}, {X: 9, Y: 10}, {X: 9, Y: 1}, {X: 0, Y: 1}},
+			}}},
+			wantArea: 40,
+		},
+		{
+			name: "Miter limit",
+			ln:   bend,
+			opts: BufferOptions{Cap: CapButt, Join: JoinMiter, MiterLimit: 1.2},
+			want: []Polygon{{Coords: [][]Point{
+				{{X: 0, Y: -1}, {X: 10, Y: -1}, {X: 11, Y: 0}, {X: 11, Y: 10}, {X: 9, Y: 10}, {X: 9, Y: 1}, {X: 0, Y: 1}},
+			}}},
+			wantArea: 39.5,
+		},
+		{
+			name: "Bevel join",
+			ln:   bend,
+			opts: BufferOptions{Cap: CapButt, Join: JoinBevel},
+			want: []Polygon{{Coords: [][]Point{
+				{{X: 0, Y: -1}, {X: 10, Y: -1}, {X: 11, Y: 0}, {X: 11, Y: 10}, {X: 9, Y: 10}, {X: 9, Y: 1}, {X: 0, Y: 1}},
+			}}},
+			wantArea: 39.5,
+		},
+		{
+			name: "Self-crossing line",
+			ln:   LineString{Width: 2, Coords: []Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: -5}}},
+			opts: BufferOptions{Cap: CapButt, Join: JoinMiter},
+			want: []Polygon{{Coords: [][]Point{
+				{{X: -1, Y: -5}, {X: 1, Y: -5}, {X: 1, Y: -1}, {X: 11, Y: -1}, {X: 11, Y: 11}, {X: -1, Y: 11}},
+				{{X: 1, Y: 1}, {X: 1, Y: 9}, {X: 9, Y: 9}, {X: 9, Y: 1}},
+			}}},
+			wantArea: 88,
+		},
+		{
+			name:     "Zero width",
+			ln:       LineString{Coords: straight.Coords},
+			want:     nil,
+			wantArea: 0,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := test.ln.Buffer(test.opts)
+			if diff := cmp.Diff(got, test.want, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+
+			var area float64
+			for _, p := range got {
+				area += p.Area()
+			}
+			if diff := cmp.Diff(area, test.wantArea, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLineString_BufferRound(t *testing.T) {
+	ln := LineString{Width: 2, Coords: []Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}}
+	polys := ln.Buffer(BufferOptions{ArcSegments: 64})
+	if diff := cmp.Diff(len(polys), 1); diff != "" {
+		t.Fatalf("mismatch (-got +want):\n%s", diff)
+	}
+	p := polys[0]
+
+	// Two straight pieces overlapping by a square unit, a quarter circle at
+	// the join and two half circles at the caps.
+	want := 39 + 1.25*math.Pi
+	if got := p.Area(); math.Abs(got-want) > 0.01 {
+		t.Errorf("got: <%v>, want: <%v>", got, want)
+	}
+
+	for _, pt := range []Point{{X: -0.99, Y: 0}, {X: 10.7, Y: -0.7}, {X: 10, Y: 10.99}} {
+		if !p.Contains(pt) {
+			t.Errorf("got: <%v>, want: <%v>", false, true)
+		}
+	}
+}
+
+func TestMap_RoadSurfaces(t *testing.T) {
+	mp := &Map{
+		Roads: []LineString{
+			{Width: 2, Coords: []Point{{X: -5, Y: 0}, {X: 5, Y: 0}}},
+			{Width: 2, Coords: []Point{{X: 0, Y: -5}, {X: 0, Y: 5}}},
+			{Width: 4, Coords: []Point{{X: 20, Y: 0}, {X: 30, Y: 0}}},
+		},
+		Rivers: []LineString{{Width: 6, Coords: []Point{{X: 0, Y: 20}, {X: 40, Y: 20}}}},
+	}
+
+	roads := mp.RoadSurfaces()
+	if diff := cmp.Diff(len(roads), 2); diff != "" {
+		t.Fatalf("mismatch (-got +want):\n%s", diff)
+	}
+
+	var area float64
+	for _, p := range roads {
+		area += p.Area()
+	}
+	// Two crossing roads of 20 square units sharing 4 and a road of 40,
+	// plus their round caps approximated by inscribed arcs.
+	want := 76 + 6*math.Pi
+	if area < want-0.5 || area > want {
+		t.Errorf("got: <%v>, want: <%v>", area, want)
+	}
+
+	rivers := mp.RiverSurfaces()
+	if diff := cmp.Diff(len(rivers), 1); diff != "" {
+		t.Fatalf("mismatch (-got +want):\n%s", diff)
+	}
+	if !rivers[0].Contains(Point{X: 20, Y: 22.9}) || rivers[0].Contains(Point{X: 20, Y: 23.1}) {
+		t.Errorf("got: <%v>, want: <%s>", rivers[0].Bounds(), "river 6 units wide")
+	}
+}
@@ -0,0 +1,638 @@
+package mfcg
+
+import (
+	"math"
+	"sort"
+)
+
+// snapTolerance is the distance within which overlay merges vertices, as a
+// fraction of the extent of its operands.
+const snapTolerance = 1e-10
+
+// overlayOp reports whether a Point belongs to the result of an overlay
+// given whether it lies within each of the operands.
+type overlayOp func(inA, inB bool) bool
+
+// Operations supported by overlay.
+var (
+	opUnion        overlayOp = func(inA, inB bool) bool { return inA || inB }
+	opIntersection overlayOp = func(inA, inB bool) bool { return inA && inB }
+	opDifference   overlayOp = func(inA, inB bool) bool { return inA && !inB }
+)
+
+// Union returns the area covered by the Polygon or any of the others. The
+// result holds one Polygon per disjoint part, with exteriors wound
+// counterclockwise and holes clockwise on a y-up plane. Polygons sharing an
+// edge are merged, so Union can join adjacent buildings into blocks.
+// Vertices closer than a ten-billionth of the operands' extent are merged,
+// so slivers narrower than that may vanish from the result.
+func (p Polygon) Union(others ...Polygon) []Polygon {
+	return overlay(append([]Polygon{p}, others...), nil, opUnion)
+}
+
+// Intersection returns the area covered by both the Polygon and other,
+// wound like the result of Union and merged to the same tolerance. It is
+// empty if the Polygons only touch.
+func (p Polygon) Intersection(other Polygon) []Polygon {
+	return overlay([]Polygon{p}, []Polygon{other}, opIntersection)
+}
+
+// Difference returns the area covered by the Polygon but by none of the
+// others, wound like the result of Union and merged to the same tolerance.
+func (p Polygon) Difference(others ...Polygon) []Polygon {
+	return overlay([]Polygon{p}, others, opDifference)
+}
+
+// segment is a straight edge from a to b.
+type segment struct {
+	a, b Point
+}
+
+// region is an operand of an overlay: the union of its Polygons, each
+// following the even-odd rule.
+type region struct {
+	polys  []Polygon
+	bounds []Rect
+}
+
+// newRegion returns the region covered by the provided Polygons.
+func newRegion(polys []Polygon) region {
+	r := region{polys: polys, bounds: make([]Rect, len(polys))}
+	for i, p := range polys {
+		r.bounds[i] = p.Bounds()
+	}
+
+	return r
+}
+
+// contains reports whether Point pt lies within any of the region's
+// Polygons.
+func (r region) contains(pt Point) bool {
+	for i, p := range r.polys {
+		if r.bounds[i].Contains(pt) && p.Contains(pt) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// overlay returns the Polygons covering every Point for which op holds,
+// given whether the Point lies within Polygons a and within Polygons b.
+//
+// The rings of both operands are arranged into a planar graph whose edges
+// are split wherever rings cross or touch. Each face of the graph then lies
+// wholly inside or outside of every input Polygon: crossing an edge toggles
+// the Polygons with an odd number of ring edges along it, so the Polygons
+// covering each face are found by walking from face to face, starting from
+// the unbounded side of each connected part of the graph. Edges whose faces
+// disagree on op are kept and joined into rings. The result is exact up to
+// snapTolerance, within which vertices are merged.
+//
+// Returned exteriors are wound counterclockwise and holes clockwise on a
+// y-up plane, every ring starts at its lowest Point and the Polygons are
+// ordered by the lowest Point of their exterior.
+func overlay(a, b []Polygon, op overlayOp) []Polygon {
+	polys := append(append([]Polygon(nil), a...), b...)
+
+	var segs []segment
+	var rings []overlayRing
+	extent := emptyRect()
+	for i, p := range polys {
+		for _, ring := range p.Coords {
+			ring = openRing(ring)
+			r := overlayRing{poly: i, pts: ring, bounds: emptyRect().extend(ring), first: len(segs)}
+			for k := range ring {
+				s := segment{a: ring[k], b: ring[(k+1)%len(ring)]}
+				if s.a != s.b {
+					segs = append(segs, s)
+				}
+			}
+			r.last = len(segs)
+			rings = append(rings, r)
+			extent = extent.extend(ring)
+		}
+	}
+	if len(segs) == 0 {
+		return nil
+	}
+
+	size := math.Max(1, math.Max(extent.Dx(), extent.Dy()))
+	nodes, edges, sources := arrange(segs, size*snapTolerance)
+
+	owner := make([]int, len(segs))
+	for _, r := range rings {
+		for k := r.first; k < r.last; k++ {
+			owner[k] = r.poly
+		}
+	}
+	toggles := make([][]int, len(edges))
+	edgeOf := make([]int, len(segs))
+	for k := range edgeOf {
+		edgeOf[k] = -1
+	}
+	for k, src := range sources {
+		for _, seg := range src {
+			toggles[k] = toggle(toggles[k], owner[seg])
+			edgeOf[seg] = k
+		}
+	}
+
+	g := newPlanarGraph(nodes, edges)
+
+	// A ring lies in a single connected part of the graph, unless it was
+	// merged into a single node.
+	comps := make([]int, len(rings))
+	for i, r := range rings {
+		comps[i] = -1
+		for k := r.first; k < r.last && comps[i] < 0; k++ {
+			if e := edgeOf[k]; e >= 0 {
+				comps[i] = g.component[g.face[2*e]]
+			}
+		}
+	}
+
+	// The unbounded face of a connected part lies within the rings of other
+	// parts surrounding it, which none of its nodes can lie on.
+	covers := g.cover(toggles, func(comp int, pt Point) []int {
+		var inside []int
+		for i, r := range rings {
+			if comps[i] >= 0 && comps[i] != comp && r.bounds.Contains(pt) && ringContains(r.pts, pt) {
+				inside = toggle(inside, r.poly)
+			}
+		}
+		return inside
+	})
+
+	// Covering Polygons are sorted, so those of a precede those of b.
+	in := func(face []int) bool {
+		return op(len(face) > 0 && face[0] < len(a), len(face) > 0 && face[len(face)-1] >= len(a))
+	}
+
+	var kept [][2]int
+	for k, e := range edges {
+		left, right := in(covers[g.face[2*k]]), in(covers[g.face[2*k+1]])
+		switch {
+		case left && !right:
+			kept = append(kept, e)
+		case right && !left:
+			kept = append(kept, [2]int{e[1], e[0]})
+		}
+	}
+
+	return assemble(nodes, kept)
+}
+
+// overlayRing is a ring of an overlay's operand. Its segments are those
+// indexed from first to last, excluded.
+type overlayRing struct {
+	poly        int
+	pts         []Point
+	bounds      Rect
+	first, last int
+}
+
+// toggle adds n to the sorted set s if it is missing and removes it
+// otherwise.
+func toggle(s []int, n int) []int {
+	i := sort.SearchInts(s, n)
+	if i < len(s) && s[i] == n {
+		return append(s[:i:i], s[i+1:]...)
+	}
+
+	t := make([]int, 0, len(s)+1)
+	t = append(t, s[:i]...)
+	t = append(t, n)
+	return append(t, s[i:]...)
+}
+
+// symmetricDifference returns the sorted set of the numbers in exactly one
+// of the sorted sets s and t.
+func symmetricDifference(s, t []int) []int {
+	var d []int
+	i, j := 0, 0
+	for i < len(s) || j < len(t) {
+		switch {
+		case j == len(t) || (i < len(s) && s[i] < t[j]):
+			d = append(d, s[i])
+			i++
+		case i == len(s) || t[j] < s[i]:
+			d = append(d, t[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+
+	return d
+}
+
+// planarGraph is the set of faces of a planar graph. Edge k of the graph is
+// traversed from its first to its second node by half-edge 2k and back by
+// half-edge 2k+1, and every half-edge has its face on its left.
+type planarGraph struct {
+	nodes     []Point
+	edges     [][2]int
+	face      []int // face holds the face of each half-edge.
+	faces     [][]int
+	component []int // component holds the connected part of the graph of each face.
+	outer     []int // outer holds the unbounded face of each connected part.
+}
+
+// newPlanarGraph returns the faces of the planar graph with the provided
+// nodes and edges. Edges must only meet at their nodes.
+func newPlanarGraph(nodes []Point, edges [][2]int) *planarGraph {
+	g := &planarGraph{nodes: nodes, edges: edges}
+
+	// The half-edges leaving each node, sorted counterclockwise.
+	out := make([][]int, len(nodes))
+	angle := make([]float64, 2*len(edges))
+	for k, e := range edges {
+		u, v := nodes[e[0]], nodes[e[1]]
+		angle[2*k] = math.Atan2(v.Y-u.Y, v.X-u.X)
+		angle[2*k+1] = math.Atan2(u.Y-v.Y, u.X-v.X)
+		out[e[0]] = append(out[e[0]], 2*k)
+		out[e[1]] = append(out[e[1]], 2*k+1)
+	}
+	pos := make([]int, 2*len(edges))
+	for _, hs := range out {
+		sort.Slice(hs, func(i, j int) bool { return angle[hs[i]] < angle[hs[j]] })
+		for i, h := range hs {
+			pos[h] = i
+		}
+	}
+
+	// The face left of a half-edge continues along the half-edge leaving its
+	// end which is first clockwise from its reverse.
+	next := func(h int) int {
+		hs := out[g.to(h)]
+		return hs[(pos[h^1]+len(hs)-1)%len(hs)]
+	}
+
+	g.face = make([]int, 2*len(edges))
+	for h := range g.face {
+		g.face[h] = -1
+	}
+	for h := range g.face {
+		if g.face[h] >= 0 {
+			continue
+		}
+		f := len(g.faces)
+		var cycle []int
+		for c := h; g.face[c] < 0; c = next(c) {
+			g.face[c] = f
+			cycle = append(cycle, c)
+		}
+		g.faces = append(g.faces, cycle)
+	}
+
+	// Connected parts of the graph are found by joining the faces on both
+	// sides of each edge.
+	parent := make([]int, len(g.faces))
+	for f := range parent {
+		parent[f] = f
+	}
+	var find func(int) int
+	find = func(f int) int {
+		if parent[f] != f {
+			parent[f] = find(parent[f])
+		}
+		return parent[f]
+	}
+	for k := range edges {
+		parent[find(g.face[2*k])] = find(g.face[2*k+1])
+	}
+
+	// The unbounded face of each part is the one of least signed area, as
+	// it winds clockwise around every other face of the part.
+	g.component = make([]int, len(g.faces))
+	ids := make(map[int]int)
+	var areas []float64
+	for f, cycle := range g.faces {
+		root := find(f)
+		c, ok := ids[root]
+		if !ok {
+			c = len(g.outer)
+			ids[root] = c
+			g.outer = append(g.outer, f)
+		}
+		g.component[f] = c
+
+		ring := make([]Point, len(cycle))
+		for i, h := range cycle {
+			ring[i] = g.nodes[g.from(h)]
+		}
+		areas = append(areas, signedArea(ring))
+		if areas[f] < areas[g.outer[c]] {
+			g.outer[c] = f
+		}
+	}
+
+	return g
+}
+
+// from returns the node half-edge h leaves.
+func (g *planarGraph) from(h int) int {
+	return g.edges[h/2][h%2]
+}
+
+// to returns the node half-edge h reaches.
+func (g *planarGraph) to(h int) int {
+	return g.edges[h/2][1-h%2]
+}
+
+// cover returns the sorted set of Polygons covering each face, given the
+// Polygons toggled by crossing each edge. outside returns the Polygons
+// covering the unbounded face of a connected part of the graph, given one
+// of its nodes.
+func (g *planarGraph) cover(toggles [][]int, outside func(comp int, pt Point) []int) [][]int {
+	covers := make([][]int, len(g.faces))
+	done := make([]bool, len(g.faces))
+	for c, f := range g.outer {
+		covers[f] = outside(c, g.nodes[g.from(g.faces[f][0])])
+		done[f] = true
+
+		queue := []int{f}
+		for len(queue) > 0 {
+			f := queue[0]
+			queue = queue[1:]
+			for _, h := range g.faces[f] {
+				other := g.face[h^1]
+				if done[other] {
+					continue
+				}
+				covers[other] = symmetricDifference(covers[f], toggles[h/2])
+				done[other] = true
+				queue = append(queue, other)
+			}
+		}
+	}
+
+	return covers
+}
+
+// arrange splits the provided segments wherever they cross or touch and
+// returns the resulting nodes along with every distinct undirected edge
+// between them and, for each edge, the indices of the segments running
+// along it. Points within tolerance of each other are merged into a single
+// node.
+func arrange(segs []segment, tolerance float64) ([]Point, [][2]int, [][]int) {
+	order := make([]int, len(segs))
+	for i := range order {
+		order[i] = i
+	}
+	minX := func(s segment) float64 { return math.Min(s.a.X, s.b.X) }
+	sort.Slice(order, func(i, j int) bool {
+		return minX(segs[order[i]]) < minX(segs[order[j]])
+	})
+
+	// Candidate pairs of segments are found through an Index over their
+	// bounds, so that long chains of segments sharing a span of X, such as
+	// the pieces of a road network, are not compared pairwise.
+	entries := make([]*indexEntry, len(segs))
+	for i, s := range segs {
+		entries[i] = &indexEntry{
+			ref:    FeatureRef{Index: i},
+			bounds: emptyRect().extend([]Point{s.a, s.b}).Inset(-tolerance),
+		}
+	}
+	ix := bulkLoad(entries)
+
+	params := make([][]float64, len(segs))
+	for i := range segs {
+		params[i] = append(params[i], 0, 1)
+	}
+
+	for i, s := range segs {
+		r := entries[i].bounds
+		ix.search(func(b Rect) bool { return b.Intersects(r) }, func(e *indexEntry) {
+			j := e.ref.Index
+			if j <= i {
+				return
+			}
+
+			ts, us := intersectSegments(s, segs[j], tolerance)
+			params[i] = append(params[i], ts...)
+			params[j] = append(params[j], us...)
+		})
+	}
+
+	sn := newSnapper(tolerance)
+	var nodes []Point
+	index := make(map[[2]int]int)
+	var edges [][2]int
+	var sources [][]int
+	for _, i := range order {
+		s := segs[i]
+		sort.Float64s(params[i])
+
+		prev := -1
+		for _, t := range params[i] {
+			pt := s.at(t)
+			n, added := sn.snap(pt, len(nodes))
+			if added {
+				nodes = append(nodes, pt)
+			}
+
+			if prev >= 0 && prev != n {
+				key := [2]int{prev, n}
+				if key[0] > key[1] {
+					key[0], key[1] = key[1], key[0]
+				}
+				k, ok := index[key]
+				if !ok {
+					k = len(edges)
+					index[key] = k
+					edges = append(edges, key)
+					sources = append(sources, nil)
+				}
+				sources[k] = append(sources[k], i)
+			}
+			prev = n
+		}
+	}
+
+	return nodes, edges, sources
+}
+
+// intersectSegments returns the parameters along s and along t of the
+// Points where the two segments cross or touch. Endpoints of either segment
+// lying within tolerance of the other count as touching it.
+func intersectSegments(s, t segment, tolerance float64) (ts, us []float64) {
+	rx, ry := s.b.X-s.a.X, s.b.Y-s.a.Y
+	qx, qy := t.b.X-t.a.X, t.b.Y-t.a.Y
+
+	for _, pt := range []Point{t.a, t.b} {
+		if segmentDistance(pt, s.a, s.b) <= tolerance {
+			ts = append(ts, project(pt, s))
+		}
+	}
+	for _, pt := range []Point{s.a, s.b} {
+		if segmentDistance(pt, t.a, t.b) <= tolerance {
+			us = append(us, project(pt, t))
+		}
+	}
+
+	denom := rx*qy - ry*qx
+	if denom == 0 {
+		return ts, us
+	}
+
+	wx, wy := t.a.X-s.a.X, t.a.Y-s.a.Y
+	tp := (wx*qy - wy*qx) / denom
+	up := (wx*ry - wy*rx) / denom
+	if tp > 0 && tp < 1 && up > 0 && up < 1 {
+		ts = append(ts, tp)
+		us = append(us, up)
+	}
+
+	return ts, us
+}
+
+// project returns the parameter along segment s of the Point of s closest to
+// pt.
+func project(pt Point, s segment) float64 {
+	dx, dy := s.b.X-s.a.X, s.b.Y-s.a.Y
+	t := ((pt.X-s.a.X)*dx + (pt.Y-s.a.Y)*dy) / (dx*dx + dy*dy)
+
+	return math.Max(0, math.Min(1, t))
+}
+
+// assemble joins the provided directed edges, each with the covered area on
+// its left, into rings and groups them into Polygons. At nodes shared by
+// several rings, each ring takes the sharpest turn available so rings which
+// touch are kept apart.
+func assemble(nodes []Point, edges [][2]int) []Polygon {
+	out := make(map[int][]int)
+	for i, e := range edges {
+		out[e[0]] = append(out[e[0]], i)
+	}
+
+	// Holes are kept along with the middle of one of their edges. No other
+	// ring runs through it, so it lies within the Polygon around the hole.
+	type hole struct {
+		ring  []Point
+		probe Point
+	}
+
+	used := make([]bool, len(edges))
+	var outers [][]Point
+	var holes []hole
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+
+		var ring []Point
+		cur := start
+		for {
+			used[cur] = true
+			ring = append(ring, nodes[edges[cur][0]])
+
+			next := nextEdge(nodes, edges, out[edges[cur][1]], cur, start, used)
+			if next < 0 || next == start {
+				break
+			}
+			cur = next
+		}
+
+		probe := Point{X: (ring[0].X + ring[1%len(ring)].X) / 2, Y: (ring[0].Y + ring[1%len(ring)].Y) / 2}
+		ring, _ = removeCollinear(ring)
+		if len(ring) < 3 {
+			continue
+		}
+		ring = rotateRing(ring)
+
+		switch a := signedArea(ring); {
+		case a > 0:
+			outers = append(outers, ring)
+		case a < 0:
+			holes = append(holes, hole{ring: ring, probe: probe})
+		}
+	}
+
+	sortRings(outers)
+	sort.Slice(holes, func(i, j int) bool {
+		return lessPoint(holes[i].ring[0], holes[j].ring[0])
+	})
+
+	polys := make([]Polygon, len(outers))
+	areas := make([]float64, len(outers))
+	for i, ring := range outers {
+		polys[i].Coords = [][]Point{ring}
+		areas[i] = signedArea(ring)
+	}
+
+	for _, h := range holes {
+		best := -1
+		for i, ring := range outers {
+			if ringContains(ring, h.probe) && (best < 0 || areas[i] < areas[best]) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			polys[best].Coords = append(polys[best].Coords, h.ring)
+		}
+	}
+
+	return polys
+}
+
+// nextEdge returns the first edge among candidates leaving the end of edge
+// cur found by sweeping clockwise from cur's reverse direction, or -1 if
+// there is none.
+// Used edges are skipped unless they are start, which closes the ring.
+func nextEdge(nodes []Point, edges [][2]int, candidates []int, cur, start int, used []bool) int {
+	from, at := nodes[edges[cur][0]], nodes[edges[cur][1]]
+	back := math.Atan2(from.Y-at.Y, from.X-at.X)
+
+	best, bestTurn := -1, math.Inf(1)
+	for _, c := range candidates {
+		if used[c] && c != start {
+			continue
+		}
+
+		to := nodes[edges[c][1]]
+		turn := back - math.Atan2(to.Y-at.Y, to.X-at.X)
+		for turn <= 0 {
+			turn += 2 * math.Pi
+		}
+		for turn > 2*math.Pi {
+			turn -= 2 * math.Pi
+		}
+		if turn < bestTurn {
+			best, bestTurn = c, turn
+		}
+	}
+
+	return best
+}
+
+// rotateRing returns the provided ring starting at its lowest Point, the one
+// with the smallest X and then the smallest Y, so equal rings compare equal.
+func rotateRing(ring []Point) []Point {
+	low := 0
+	for i, pt := range ring {
+		if lessPoint(pt, ring[low]) {
+			low = i
+		}
+	}
+
+	return append(ring[low:len(ring):len(ring)], ring[:low]...)
+}
+
+// sortRings sorts the provided rings by their first Point.
+func sortRings(rings [][]Point) {
+	sort.Slice(rings, func(i, j int) bool {
+		return lessPoint(rings[i][0], rings[j][0])
+	})
+}
+
+// lessPoint reports whether Point a precedes b, ordering by X and then by Y.
+func lessPoint(a, b Point) bool {
+	if a.X != b.X {
+		return a.X < b.X
+	}
+	return a.Y < b.Y
+}
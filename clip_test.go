@@ -0,0 +1,267 @@
+package mfcg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// square returns the counterclockwise square of the provided size whose
+// lower left corner is (x, y).
+func square(x, y, size float64) Polygon {
+	return Polygon{Coords: [][]Point{{
+		{X: x, Y: y}, {X: x + size, Y: y}, {X: x + size, Y: y + size}, {X: x, Y: y + size},
+	}}}
+}
+
+func TestPolygon_Union(t *testing.T) {
+	tests := []struct {
+		name   string
+		p      Polygon
+		others []Polygon
+		want   []Polygon
+	}{
+		{
+			name:   "Overlapping",
+			p:      square(0, 0, 10),
+			others: []Polygon{square(5, 5, 10)},
+			want: []Polygon{{Coords: [][]Point{{
+				{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 5}, {X: 15, Y: 5},
+				{X: 15, Y: 15}, {X: 5, Y: 15}, {X: 5, Y: 10}, {X: 0, Y: 10},
+			}}}},
+		},
+		{
+			name:   "Adjacent",
+			p:      square(0, 0, 10),
+			others: []Polygon{square(10, 0, 10)},
+			want: []Polygon{{Coords: [][]Point{{
+				{X: 0, Y: 0}, {X: 20, Y: 0}, {X: 20, Y: 10}, {X: 0, Y: 10},
+			}}}},
+		},
+		{
+			name:   "Disjoint",
+			p:      square(0, 0, 10),
+			others: []Polygon{square(20, 0, 10)},
+			want:   []Polygon{square(0, 0, 10), square(20, 0, 10)},
+		},
+		{
+			name: "Enclosing a hole",
+			p:    Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 30, Y: 0}, {X: 30, Y: 10}, {X: 0, Y: 10}}}},
+			others: []Polygon{
+				{Coords: [][]Point{{{X: 0, Y: 20}, {X: 30, Y: 20}, {X: 30, Y: 30}, {X: 0, Y: 30}}}},
+				{Coords: [][]Point{{{X: 0, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 20}, {X: 0, Y: 20}}}},
+				{Coords: [][]Point{{{X: 20, Y: 10}, {X: 30, Y: 10}, {X: 30, Y: 20}, {X: 20, Y: 20}}}},
+			},
+			want: []Polygon{{Coords: [][]Point{
+				{{X: 0, Y: 0}, {X: 30, Y: 0}, {X: 30, Y: 30}, {X: 0, Y: 30}},
+				{{X: 10, Y: 10}, {X: 10, Y: 20}, {X: 20, Y: 20}, {X: 20, Y: 10}},
+			}}},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := test.p.Union(test.others...)
+			if diff := cmp.Diff(got, test.want, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPolygon_Intersection(t *testing.T) {
+	tests := []struct {
+		name  string
+		p     Polygon
+		other Polygon
+		want  []Polygon
+	}{
+		{
+			name:  "Overlapping",
+			p:     square(0, 0, 10),
+			other: square(5, 5, 10),
+			want:  []Polygon{square(5, 5, 5)},
+		},
+		{
+			name:  "Touching",
+			p:     square(0, 0, 10),
+			other: square(10, 0, 10),
+			want:  nil,
+		},
+		{
+			name: "Split by a hole",
+			p: Polygon{Coords: [][]Point{
+				{{X: 0, Y: 0}, {X: 30, Y: 0}, {X: 30, Y: 30}, {X: 0, Y: 30}},
+				{{X: 10, Y: 10}, {X: 10, Y: 20}, {X: 20, Y: 20}, {X: 20, Y: 10}},
+			}},
+			other: Polygon{Coords: [][]Point{{{X: -5, Y: 12}, {X: 35, Y: 12}, {X: 35, Y: 18}, {X: -5, Y: 18}}}},
+			want: []Polygon{
+				{Coords: [][]Point{{{X: 0, Y: 12}, {X: 10, Y: 12}, {X: 10, Y: 18}, {X: 0, Y: 18}}}},
+				{Coords: [][]Point{{{X: 20, Y: 12}, {X: 30, Y: 12}, {X: 30, Y: 18}, {X: 20, Y: 18}}}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := test.p.Intersection(test.other)
+			if diff := cmp.Diff(got, test.want, cmpopts.EquateApprox(0, 1e-9), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPolygon_Difference(t *testing.T) {
+	tests := []struct {
+		name   string
+		p      Polygon
+		others []Polygon
+		want   []Polygon
+	}{
+		{
+			name:   "Corner",
+			p:      square(0, 0, 10),
+			others: []Polygon{square(5, 5, 10)},
+			want: []Polygon{{Coords: [][]Point{{
+				{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 5}, {X: 5, Y: 5}, {X: 5, Y: 10}, {X: 0, Y: 10},
+			}}}},
+		},
+		{
+			name:   "Hole",
+			p:      square(0, 0, 10),
+			others: []Polygon{square(2, 2, 2)},
+			want: []Polygon{{Coords: [][]Point{
+				{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}},
+				{{X: 2, Y: 2}, {X: 2, Y: 4}, {X: 4, Y: 4}, {X: 4, Y: 2}},
+			}}},
+		},
+		{
+			name:   "Covered",
+			p:      square(2, 2, 2),
+			others: []Polygon{square(0, 0, 10)},
+			want:   nil,
+		},
+		{
+			name:   "Several",
+			p:      square(0, 0, 30),
+			others: []Polygon{square(0, 0, 10), square(20, 20, 10), square(0, 10, 10), square(0, 20, 10)},
+			want: []Polygon{{Coords: [][]Point{{
+				{X: 10, Y: 0}, {X: 30, Y: 0}, {X: 30, Y: 20}, {X: 20, Y: 20}, {X: 20, Y: 30}, {X: 10, Y: 30},
+			}}}},
+		},
+		{
+			name:   "Narrow slit",
+			p:      square(0, 0, 100),
+			others: []Polygon{{Coords: [][]Point{{{X: -10, Y: 50}, {X: 110, Y: 50}, {X: 110, Y: 50 + 1e-6}, {X: -10, Y: 50 + 1e-6}}}}},
+			want: []Polygon{
+				{Coords: [][]Point{{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 50}, {X: 0, Y: 50}}}},
+				{Coords: [][]Point{{{X: 0, Y: 50 + 1e-6}, {X: 100, Y: 50 + 1e-6}, {X: 100, Y: 100}, {X: 0, Y: 100}}}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := test.p.Difference(test.others...)
+			if diff := cmp.Diff(got, test.want, cmpopts.EquateApprox(0, 1e-9), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPolygon_booleanNarrow(t *testing.T) {
+	// Each pair of Polygons meets along edges much closer than the extent of
+	// the Polygons, leaving slivers between them.
+	base := Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 10}, {X: 0, Y: 10}}}}
+	tilted := Affine{A: 1, B: -1e-8, D: 1e-8, E: 1}
+	tests := []struct {
+		name      string
+		p, q      Polygon
+		wantInter float64
+	}{
+		{
+			name:      "Nearly parallel edges",
+			wantInter: 1000,
+			p:         base,
+			q: Polygon{Coords: [][]Point{{
+				tilted.Apply(Point{X: 0, Y: 0}), tilted.Apply(Point{X: 100, Y: 0}),
+				tilted.Apply(Point{X: 100, Y: 10}), tilted.Apply(Point{X: 0, Y: 10}),
+			}}},
+		},
+		{
+			name:      "Narrow overlap",
+			wantInter: 1e-4,
+			p:         base,
+			q:         Polygon{Coords: [][]Point{{{X: 0, Y: 10 - 1e-6}, {X: 100, Y: 10 - 1e-6}, {X: 100, Y: 20}, {X: 0, Y: 20}}}},
+		},
+		{
+			name:      "Narrow gap",
+			wantInter: 0,
+			p:         base,
+			q:         Polygon{Coords: [][]Point{{{X: 0, Y: 10 + 1e-6}, {X: 100, Y: 10 + 1e-6}, {X: 100, Y: 20}, {X: 0, Y: 20}}}},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			area := func(polys []Polygon) float64 {
+				var sum float64
+				for _, p := range polys {
+					sum += p.Area()
+				}
+				return sum
+			}
+
+			union := area(test.p.Union(test.q))
+			inter := area(test.p.Intersection(test.q))
+			diff := area(test.p.Difference(test.q))
+
+			if got, want := union, test.p.Area()+test.q.Area()-inter; math.Abs(got-want) > 1e-9 {
+				t.Errorf("got: <%v>, want: <%v>", got, want)
+			}
+			if got, want := diff, test.p.Area()-inter; math.Abs(got-want) > 1e-9 {
+				t.Errorf("got: <%v>, want: <%v>", got, want)
+			}
+			if math.Abs(inter-test.wantInter) > 1e-4*test.wantInter+1e-9 {
+				t.Errorf("got: <%v>, want: <%v>", inter, test.wantInter)
+			}
+		})
+	}
+}
+
+func TestPolygon_booleanFixture(t *testing.T) {
+	mp := testMap(t, testFileDistricts)
+	docks, market := mp.Districts[0].Polygon, mp.Districts[1].Polygon
+
+	city := docks.Union(market)
+	if len(city) != 1 || len(city[0].Coords) != 1 || city[0].Area() != 40000 {
+		t.Errorf("got: <%v>, want: <%s>", city, "single 200 by 200 Polygon")
+	}
+
+	if got := docks.Intersection(market); len(got) != 0 {
+		t.Errorf("got: <%v>, want: <%v>", got, nil)
+	}
+
+	open := market.Difference(mp.Squares...)
+	if len(open) != 1 || len(open[0].Coords) != 2 || open[0].Area() != 20000-100 {
+		t.Errorf("got: <%v>, want: <%s>", open, "Market with a hole for its square")
+	}
+
+	for i, b := range mp.Buildings {
+		var area float64
+		for _, p := range b.Intersection(market) {
+			area += p.Area()
+		}
+		want := map[int]float64{0: 0, 1: 100, 2: 0}[i]
+		if area != want {
+			t.Errorf("got: <%v>, want: <%v>", area, want)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package mfcg
+
+import "sort"
+
+// ClipOptions configures how Clip crops a Map.
+type ClipOptions struct {
+	Recenter bool // Recenter moves the center of the region's bounds to the origin.
+}
+
+// Clip returns a new Map holding the part of the Map lying within the
+// provided region, such as a single neighbourhood. Polygons are intersected
+// with the region, possibly splitting them into several Polygons; the Earth
+// keeps only its largest piece. LineStrings are split where they cross the
+// region's boundary and trees outside of it are dropped. Walls are lines
+// rather than areas, so they are split like LineStrings and the returned
+// Map's walls are open. Extra features are kept unchanged if any of their
+// Points lies within the region, which rules out those kept as Raw JSON.
+// The MetaData is kept as is. The Map itself is left untouched.
+func (m *Map) Clip(region Polygon, opts ClipOptions) *Map {
+	rg := newRegion([]Polygon{region})
+	clipped := &Map{MetaData: m.MetaData}
+
+	if m.Earth.Coords != nil {
+		var best float64
+		for _, p := range m.Earth.Intersection(region) {
+			if a := p.Area(); a > best {
+				clipped.Earth, best = p, a
+			}
+		}
+		clipped.Earth.Width = m.Earth.Width
+	}
+
+	clipped.Planks = clipLineStrings(m.Planks, rg)
+	clipped.Rivers = clipLineStrings(m.Rivers, rg)
+	clipped.Roads = clipLineStrings(m.Roads, rg)
+	clipped.Buildings = clipPolygons(m.Buildings, region)
+	clipped.Fields = clipPolygons(m.Fields, region)
+	clipped.Greens = clipPolygons(m.Greens, region)
+	clipped.Prisms = clipPolygons(m.Prisms, region)
+	clipped.Squares = clipPolygons(m.Squares, region)
+	clipped.Walls = m.clipWalls(rg)
+	clipped.OpenWalls = clipped.Walls != nil
+	clipped.Water = clipPolygons(m.Water, region)
+
+	if m.Trees != nil {
+		clipped.Trees = []Point{}
+		for _, pt := range m.Trees {
+			if rg.contains(pt) {
+				clipped.Trees = append(clipped.Trees, pt)
+			}
+		}
+	}
+
+	if m.Districts != nil {
+		clipped.Districts = []District{}
+		for _, d := range m.Districts {
+			for _, p := range d.Intersection(region) {
+				p.Width = d.Width
+				clipped.Districts = append(clipped.Districts, District{Name: d.Name, Polygon: p})
+			}
+		}
+	}
+
+	for _, ex := range m.Extra {
+		inside := false
+		g := ex.Geometry.clone()
+		mapGeometry(&g, func(pt Point) Point {
+			inside = inside || rg.contains(pt)
+			return pt
+		})
+		if inside {
+			ex.Geometry = g
+			clipped.Extra = append(clipped.Extra, ex)
+		}
+	}
+
+	if opts.Recenter {
+		c := region.Bounds().Center()
+		clipped.mapPoints(Translate(-c.X, -c.Y).Apply)
+	}
+
+	return clipped
+}
+
+// clipPolygons returns the parts of the provided Polygons lying within
+// region, keeping their widths. A nil slice is returned as is.
+func clipPolygons(polys []Polygon, region Polygon) []Polygon {
+	if polys == nil {
+		return nil
+	}
+
+	clipped := []Polygon{}
+	for _, p := range polys {
+		for _, part := range p.Intersection(region) {
+			part.Width = p.Width
+			clipped = append(clipped, part)
+		}
+	}
+
+	return clipped
+}
+
+// clipLineStrings returns the parts of the provided LineStrings lying within
+// rg, keeping their widths. A LineString crossing the region's boundary is
+// split into one LineString per part. A nil slice is returned as is.
+func clipLineStrings(lines []LineString, rg region) []LineString {
+	if lines == nil {
+		return nil
+	}
+
+	clipped := []LineString{}
+	for _, ln := range lines {
+		for _, part := range clipPath(ln.Coords, rg) {
+			clipped = append(clipped, LineString{Width: ln.Width, Coords: part})
+		}
+	}
+
+	return clipped
+}
+
+// clipWalls returns the parts of the Map's walls lying within rg as open
+// paths, keeping their widths. Each wall becomes a Polygon holding one path
+// per part of its rings and is dropped if no part remains. A ring lying
+// wholly within rg is kept closed. A nil slice is returned as is.
+func (m *Map) clipWalls(rg region) []Polygon {
+	if m.Walls == nil {
+		return nil
+	}
+
+	clipped := []Polygon{}
+	for _, p := range m.Walls {
+		var paths [][]Point
+		for _, ring := range p.Coords {
+			pts := m.wallPath(ring)
+			parts := clipPath(pts, rg)
+
+			// A closed ring cut by the boundary starts and ends within one
+			// of its parts, which is joined back together.
+			if n := len(parts); n > 1 && isClosed(pts) && parts[0][0] == pts[0] {
+				last := parts[n-1]
+				if last[len(last)-1] == pts[0] {
+					parts[0] = append(last, parts[0][1:]...)
+					parts = parts[:n-1]
+				}
+			}
+			paths = append(paths, parts...)
+		}
+		if len(paths) > 0 {
+			clipped = append(clipped, Polygon{Width: p.Width, Coords: paths})
+		}
+	}
+
+	return clipped
+}
+
+// clipPath returns the parts of the line through pts lying within rg, one
+// per stretch of the line between crossings of the region's boundary.
+func clipPath(pts []Point, rg region) [][]Point {
+	var parts [][]Point
+	var part []Point
+	flush := func() {
+		if len(part) > 1 {
+			parts = append(parts, part)
+		}
+		part = nil
+	}
+
+	for i := 1; i < len(pts); i++ {
+		s := segment{a: pts[i-1], b: pts[i]}
+		if s.a == s.b {
+			continue
+		}
+
+		for _, piece := range rg.split(s) {
+			if len(part) > 0 && part[len(part)-1] != piece.a {
+				flush()
+			}
+			if len(part) == 0 {
+				part = append(part, piece.a)
+			}
+			part = append(part, piece.b)
+		}
+	}
+	flush()
+
+	return parts
+}
+
+// split returns the pieces of segment s lying within the region, in order.
+// Pieces are cut wherever s crosses the boundary of one of the region's
+// Polygons.
+func (r region) split(s segment) []segment {
+	params := []float64{0, 1}
+	for _, p := range r.polys {
+		for _, ring := range p.Coords {
+			ring = openRing(ring)
+			for i := range ring {
+				t := segment{a: ring[i], b: ring[(i+1)%len(ring)]}
+				if t.a == t.b {
+					continue
+				}
+				ts, _ := intersectSegments(s, t, 0)
+				params = append(params, ts...)
+			}
+		}
+	}
+	sort.Float64s(params)
+
+	var pieces []segment
+	for i := 1; i < len(params); i++ {
+		t0, t1 := params[i-1], params[i]
+		if t1 == t0 {
+			continue
+		}
+
+		mid := (t0 + t1) / 2
+		if !r.contains(s.at(mid)) {
+			continue
+		}
+
+		piece := segment{a: s.at(t0), b: s.at(t1)}
+		if n := len(pieces); n > 0 && pieces[n-1].b == piece.a {
+			// Merge with the previous piece so the Points of s are not
+			// multiplied where it only touches the boundary.
+			pieces[n-1].b = piece.b
+			continue
+		}
+		pieces = append(pieces, piece)
+	}
+
+	return pieces
+}
+
+// at returns the Point of the segment at parameter t, from a at 0 to b at 1.
+func (s segment) at(t float64) Point {
+	switch t {
+	case 0:
+		return s.a
+	case 1:
+		return s.b
+	}
+
+	return Point{X: s.a.X + t*(s.b.X-s.a.X), Y: s.a.Y + t*(s.b.Y-s.a.Y)}
+}
@@ -0,0 +1,109 @@
+package mfcg
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestMap_Clip(t *testing.T) {
+	region := Polygon{Coords: [][]Point{{{X: -35, Y: -35}, {X: 25, Y: -35}, {X: 25, Y: 25}, {X: -35, Y: 25}}}}
+
+	tests := []struct {
+		name string
+		mp   *Map
+		opts ClipOptions
+		want *Map
+	}{
+		{
+			name: "Districts fixture",
+			mp:   testMap(t, testFileDistricts),
+			want: &Map{
+				MetaData: MetaData{RoadWidth: 8, Generator: "mfcg", Version: "0.7.7a"},
+				Roads: []LineString{
+					{Width: 8, Coords: []Point{{X: -35, Y: 1.875}, {X: -10, Y: 5}, {X: 25, Y: 8.5}}},
+				},
+				Buildings: []Polygon{
+					{Coords: [][]Point{{{X: -35, Y: -35}, {X: -30, Y: -35}, {X: -30, Y: -30}, {X: -35, Y: -30}}}},
+					{Coords: [][]Point{{{X: 20, Y: 20}, {X: 25, Y: 20}, {X: 25, Y: 25}, {X: 20, Y: 25}}}},
+				},
+				Squares: []Polygon{
+					{Coords: [][]Point{{{X: 5, Y: -20}, {X: 15, Y: -20}, {X: 15, Y: -10}, {X: 5, Y: -10}}}},
+				},
+				Districts: []District{
+					{Name: "Docks", Polygon: Polygon{Coords: [][]Point{{{X: -35, Y: -35}, {X: 0, Y: -35}, {X: 0, Y: 25}, {X: -35, Y: 25}}}}},
+					{Name: "Market", Polygon: Polygon{Coords: [][]Point{{{X: 0, Y: -35}, {X: 25, Y: -35}, {X: 25, Y: 25}, {X: 0, Y: 25}}}}},
+				},
+			},
+		},
+		{
+			name: "Lines, points and extras",
+			mp: &Map{
+				Earth:  Polygon{Coords: [][]Point{{{X: -100, Y: -100}, {X: 100, Y: -100}, {X: 100, Y: 100}, {X: -100, Y: 100}}}},
+				Rivers: []LineString{{Width: 20, Coords: []Point{{X: -40, Y: 0}, {X: 0, Y: 0}, {X: 0, Y: 40}, {X: 10, Y: 40}, {X: 10, Y: 0}, {X: 40, Y: 0}}}},
+				Trees:  []Point{{X: 0, Y: 0}, {X: 30, Y: 30}},
+				Walls:  []Polygon{},
+				Extra: []Feature{
+					{ID: "well", Geometry: Geometry{Type: "Point", Coordinates: Point{X: 1, Y: 1}}},
+					{ID: "well", Geometry: Geometry{Type: "Point", Coordinates: Point{X: 100, Y: 1}}},
+				},
+			},
+			opts: ClipOptions{Recenter: true},
+			want: &Map{
+				Earth: Polygon{Coords: [][]Point{{{X: -30, Y: -30}, {X: 30, Y: -30}, {X: 30, Y: 30}, {X: -30, Y: 30}}}},
+				Rivers: []LineString{
+					{Width: 20, Coords: []Point{{X: -30, Y: 5}, {X: 5, Y: 5}, {X: 5, Y: 30}}},
+					{Width: 20, Coords: []Point{{X: 15, Y: 30}, {X: 15, Y: 5}, {X: 30, Y: 5}}},
+				},
+				Trees:     []Point{{X: 5, Y: 5}},
+				Walls:     []Polygon{},
+				OpenWalls: true,
+				Extra: []Feature{
+					{ID: "well", Geometry: Geometry{Type: "Point", Coordinates: Point{X: 6, Y: 6}}},
+				},
+			},
+		},
+		{
+			name: "Walls",
+			mp: &Map{
+				Walls: []Polygon{
+					{Width: 5, Coords: [][]Point{{{X: 0, Y: 0}, {X: -50, Y: 0}, {X: -50, Y: -50}, {X: 0, Y: -50}}}},
+					{Width: 5, Coords: [][]Point{{{X: -10, Y: -10}, {X: 10, Y: -10}, {X: 10, Y: 10}}}},
+					{Width: 5, Coords: [][]Point{{{X: 50, Y: 50}, {X: 60, Y: 50}, {X: 60, Y: 60}}}},
+				},
+			},
+			want: &Map{
+				Walls: []Polygon{
+					{Width: 5, Coords: [][]Point{{{X: 0, Y: -35}, {X: 0, Y: 0}, {X: -35, Y: 0}}}},
+					{Width: 5, Coords: [][]Point{{{X: -10, Y: -10}, {X: 10, Y: -10}, {X: 10, Y: 10}, {X: -10, Y: -10}}}},
+				},
+				OpenWalls: true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			before, err := json.Marshal(test.mp)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := test.mp.Clip(region, test.opts)
+			if diff := cmp.Diff(got, test.want, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+
+			after, err := json.Marshal(test.mp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(string(after), string(before)); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
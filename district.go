@@ -0,0 +1,167 @@
+package mfcg
+
+import "encoding/json"
+
+// District is a named region of a Map, such as a city's docks or market.
+type District struct {
+	Name string `json:"name"`
+	Polygon
+}
+
+// DistrictMembers lists the features of a Map falling within a District.
+// Each field holds indices into the Map's corresponding layer.
+type DistrictMembers struct {
+	Buildings []int
+	Squares   []int
+	Roads     []int
+}
+
+// districtGeometry represents one of MFCG's proprietary district geometries.
+type districtGeometry struct {
+	Type        string    `json:"type"`
+	Name        string    `json:"name"`
+	Width       float64   `json:"width,omitempty"`
+	Coordinates [][]Point `json:"coordinates"`
+}
+
+// geosToDistricts returns a slice of Districts each corresponding to the
+// provided GeometryCollection data. The data must conform to a slice of
+// MFCG's proprietary polygon geometries carrying a name, either as a member
+// of the geometry or of its properties.
+func geosToDistricts(data []byte) ([]District, error) {
+	var geos []json.RawMessage
+	if err := json.Unmarshal(data, &geos); err != nil {
+		return nil, err
+	}
+
+	var dists []District
+	for i, g := range geos {
+		d, err := geoToDistrict(g)
+		if err != nil {
+			pe := locate(err)
+			pe.Geometry = i
+			return nil, pe
+		}
+
+		dists = append(dists, *d)
+	}
+
+	return dists, nil
+}
+
+// geoToDistrict returns a District corresponding to the provided geometry
+// data.
+func geoToDistrict(data []byte) (*District, error) {
+	var geo struct {
+		Name       string `json:"name"`
+		Properties struct {
+			Name string `json:"name"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &geo); err != nil {
+		return nil, err
+	}
+
+	p, err := geoToPolygon(data)
+	if err != nil {
+		return nil, err
+	}
+
+	name := geo.Name
+	if name == "" {
+		name = geo.Properties.Name
+	}
+
+	return &District{Name: name, Polygon: *p}, nil
+}
+
+// districtsToGeos returns the GeometryCollection data corresponding to the
+// provided Districts.
+func districtsToGeos(dists []District) ([]byte, error) {
+	geos := make([]districtGeometry, len(dists))
+	for i, d := range dists {
+		geos[i] = districtGeometry{
+			Type:        typePolygon,
+			Name:        d.Name,
+			Width:       d.Width,
+			Coordinates: d.Coords,
+		}
+	}
+
+	return json.Marshal(geos)
+}
+
+// DistrictOf returns the District containing Point pt, or nil if no
+// District contains it.
+func (m *Map) DistrictOf(pt Point) *District {
+	if i := m.districtIndex(pt); i >= 0 {
+		return &m.Districts[i]
+	}
+
+	return nil
+}
+
+// DistrictMembers returns the features falling within each of the Map's
+// Districts, in the order of the Districts. Buildings and squares belong to
+// the District containing their centroid. Roads belong to every District
+// one of their segments passes through, even if none of their Points lies
+// within it.
+func (m *Map) DistrictMembers() []DistrictMembers {
+	members := make([]DistrictMembers, len(m.Districts))
+	for i, b := range m.Buildings {
+		if d := m.districtIndex(b.Centroid()); d >= 0 {
+			members[d].Buildings = append(members[d].Buildings, i)
+		}
+	}
+
+	for i, sq := range m.Squares {
+		if d := m.districtIndex(sq.Centroid()); d >= 0 {
+			members[d].Squares = append(members[d].Squares, i)
+		}
+	}
+
+	for i, ln := range m.Roads {
+		for d := range m.Districts {
+			if m.Districts[d].crosses(ln.Coords) {
+				members[d].Roads = append(members[d].Roads, i)
+			}
+		}
+	}
+
+	return members
+}
+
+// crosses reports whether the line through pts passes through the District:
+// either one of its Points lies within the District or one of its segments
+// meets the District's boundary.
+func (d *District) crosses(pts []Point) bool {
+	for _, pt := range pts {
+		if d.Contains(pt) {
+			return true
+		}
+	}
+
+	for i := 1; i < len(pts); i++ {
+		for _, ring := range d.Coords {
+			for j, k := 0, len(ring)-1; j < len(ring); k, j = j, j+1 {
+				if segmentsIntersect(pts[i-1], pts[i], ring[k], ring[j]) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// districtIndex returns the index of the District containing Point pt, or
+// -1 if no District contains it.
+func (m *Map) districtIndex(pt Point) int {
+	for i := range m.Districts {
+		if m.Districts[i].Contains(pt) {
+			return i
+		}
+	}
+
+	return -1
+}
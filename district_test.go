@@ -0,0 +1,122 @@
+package mfcg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testFileDistricts string = "./test_data/mapDistricts.json"
+
+func Test_geosToDistricts(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []District
+		wantErr bool
+	}{
+		{
+			name: "Geometry names",
+			data: []byte(`[
+				{"type": "Polygon", "name": "Docks", "coordinates": [[[1, 1], [2, 2]]]},
+				{"type": "Polygon", "name": "Market", "coordinates": [[[3, 3]]]}
+			]`),
+			want: []District{
+				{Name: "Docks", Polygon: Polygon{Coords: [][]Point{{{X: 1, Y: 1}, {X: 2, Y: 2}}}}},
+				{Name: "Market", Polygon: Polygon{Coords: [][]Point{{{X: 3, Y: 3}}}}},
+			},
+		},
+		{
+			name: "Property names",
+			data: []byte(`[{"type": "Polygon", "properties": {"name": "Castle"}, "coordinates": [[[1, 1]]]}]`),
+			want: []District{
+				{Name: "Castle", Polygon: Polygon{Coords: [][]Point{{{X: 1, Y: 1}}}}},
+			},
+		},
+		{
+			name: "Unnamed",
+			data: []byte(`[{"type": "Polygon", "coordinates": [[[1, 1]]]}]`),
+			want: []District{
+				{Polygon: Polygon{Coords: [][]Point{{{X: 1, Y: 1}}}}},
+			},
+		},
+		{
+			name:    "Invalid name",
+			data:    []byte(`[{"type": "Polygon", "name": 12, "coordinates": [[[1, 1]]]}]`),
+			wantErr: true,
+		},
+		{
+			name:    "No data",
+			data:    []byte(``),
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := geosToDistricts(test.data)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got: <%v>, want error: <%v>", err, test.wantErr)
+			}
+
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMap_DistrictOf(t *testing.T) {
+	mp := testMap(t, testFileDistricts)
+	tests := []struct {
+		name string
+		pt   Point
+		want string
+	}{
+		{name: "Docks", pt: Point{X: -50, Y: 20}, want: "Docks"},
+		{name: "Market", pt: Point{X: 50, Y: -20}, want: "Market"},
+		{name: "Outside", pt: Point{X: 150, Y: 0}, want: ""},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var got string
+			if d := mp.DistrictOf(test.pt); d != nil {
+				got = d.Name
+			}
+
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMap_DistrictMembers(t *testing.T) {
+	mp := testMap(t, testFileDistricts)
+	want := []DistrictMembers{
+		{Buildings: []int{0}, Roads: []int{0}},
+		{Buildings: []int{1}, Squares: []int{0}, Roads: []int{0, 1}},
+	}
+
+	if diff := cmp.Diff(mp.DistrictMembers(), want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestMap_DistrictMembers_crossingRoad(t *testing.T) {
+	// The road runs straight through the first District without a single
+	// Point inside it and stops short of the second.
+	mp := &Map{
+		Districts: []District{
+			{Name: "Market", Polygon: square(0, 0, 10)},
+			{Name: "Docks", Polygon: square(30, 0, 10)},
+		},
+		Roads: []LineString{{Width: 2, Coords: []Point{{X: -5, Y: 5}, {X: 15, Y: 5}, {X: 20, Y: 5}}}},
+	}
+	want := []DistrictMembers{{Roads: []int{0}}, {}}
+
+	if diff := cmp.Diff(mp.DistrictMembers(), want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
@@ -0,0 +1,65 @@
+package mfcg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParseError describes which part of MFCG data failed to decode. Indices
+// which do not apply to the failure are set to -1.
+type ParseError struct {
+	ID       string // ID is the ID of the failing feature, which names its layer.
+	Feature  int    // Feature is the position of the failing feature within the collection.
+	Geometry int    // Geometry is the position of the failing geometry within its feature.
+	Ring     int    // Ring is the position of the failing ring within its polygon.
+	Point    int    // Point is the position of the failing Point within its ring or line.
+	Err      error  // Err is the underlying error.
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	var loc []string
+	if e.Feature >= 0 {
+		loc = append(loc, fmt.Sprintf("feature %d", e.Feature))
+	}
+	if e.ID != "" {
+		loc = append(loc, fmt.Sprintf("id %q", e.ID))
+	}
+	if e.Geometry >= 0 {
+		loc = append(loc, fmt.Sprintf("geometry %d", e.Geometry))
+	}
+	if e.Ring >= 0 {
+		loc = append(loc, fmt.Sprintf("ring %d", e.Ring))
+	}
+	if e.Point >= 0 {
+		loc = append(loc, fmt.Sprintf("point %d", e.Point))
+	}
+
+	if len(loc) == 0 {
+		return "cannot parse MFCG data: " + e.Err.Error()
+	}
+	return "cannot parse MFCG data at " + strings.Join(loc, ", ") + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// locate returns err as a *ParseError so its location can be filled in. If
+// err is not a *ParseError, it is wrapped in one without a location.
+func locate(err error) *ParseError {
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		return pe
+	}
+
+	return &ParseError{
+		Feature:  -1,
+		Geometry: -1,
+		Ring:     -1,
+		Point:    -1,
+		Err:      err,
+	}
+}
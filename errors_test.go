@@ -0,0 +1,143 @@
+package mfcg
+
+import (
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestNew_ParseError(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *ParseError
+		wantErr error
+	}{
+		{
+			name: "Invalid earth point",
+			data: `{"features": [
+				{"id": "values"},
+				{"type": "Polygon", "id": "earth", "coordinates": [[[1, 2], [3, 4]], [[5, 6], [7, "foo"]]]}
+			]}`,
+			want:    &ParseError{ID: IDEarth, Feature: 1, Geometry: -1, Ring: 1, Point: 1},
+			wantErr: ErrPointY,
+		},
+		{
+			name: "Invalid building point",
+			data: `{"features": [
+				{"type": "MultiPolygon", "id": "buildings", "coordinates": [[[[1, 2]]], [[[3, 4]], [[5, 6, 7]]]]}
+			]}`,
+			want:    &ParseError{ID: IDBuildings, Feature: 0, Geometry: 1, Ring: 1, Point: 0},
+			wantErr: ErrPointLength,
+		},
+		{
+			name: "Invalid road point",
+			data: `{"features": [
+				{"type": "GeometryCollection", "id": "roads", "geometries": [
+					{"type": "LineString", "width": 8, "coordinates": [[1, 2]]},
+					{"type": "LineString", "width": 8, "coordinates": [[1, 2], [3, 4], ["x", 5]]}
+				]}
+			]}`,
+			want:    &ParseError{ID: IDRoads, Feature: 0, Geometry: 1, Ring: -1, Point: 2},
+			wantErr: ErrPointX,
+		},
+		{
+			name: "Invalid wall width",
+			data: `{"features": [
+				{"id": "values"},
+				{"id": "earth", "coordinates": []},
+				{"type": "GeometryCollection", "id": "walls", "geometries": [
+					{"type": "Polygon", "width": "wide", "coordinates": [[[1, 2]]]}
+				]}
+			]}`,
+			want: &ParseError{ID: IDWalls, Feature: 2, Geometry: 0, Ring: -1, Point: -1},
+		},
+		{
+			name: "Invalid values",
+			data: `{"features": [
+				{"id": "earth", "coordinates": []},
+				{"type": "Feature", "id": "values", "roadWidth": "wide"}
+			]}`,
+			want: &ParseError{Feature: 1, Geometry: -1, Ring: -1, Point: -1},
+		},
+		{
+			name: "Invalid collection",
+			data: `[]`,
+			want: &ParseError{Feature: -1, Geometry: -1, Ring: -1, Point: -1},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(strings.NewReader(test.data))
+
+			var got *ParseError
+			if !errors.As(err, &got) {
+				t.Fatalf("got: <%v>, want *ParseError", err)
+			}
+
+			if diff := cmp.Diff(got, test.want, cmpopts.IgnoreFields(ParseError{}, "Err")); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+
+			if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+				t.Errorf("got: <%v>, want wrapped error: <%v>", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew_ParseErrorTestData(t *testing.T) {
+	f, err := os.Open(testFileInvalid)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	_, err = New(f)
+	want := `cannot parse MFCG data at feature 0, id "earth", ring 0, point 0: expecting float64 for Point's X field`
+	if diff := cmp.Diff(err.Error(), want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	if !errors.Is(err, ErrPointX) {
+		t.Errorf("got: <%v>, want wrapped error: <%v>", err, ErrPointX)
+	}
+}
+
+func TestParseError_Error(t *testing.T) {
+	cause := errors.New("foo")
+	tests := []struct {
+		name string
+		err  *ParseError
+		want string
+	}{
+		{
+			name: "Full location",
+			err:  &ParseError{ID: "walls", Feature: 3, Geometry: 1, Ring: 0, Point: 7, Err: cause},
+			want: `cannot parse MFCG data at feature 3, id "walls", geometry 1, ring 0, point 7: foo`,
+		},
+		{
+			name: "No location",
+			err:  locate(cause),
+			want: `cannot parse MFCG data: foo`,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if diff := cmp.Diff(test.err.Error(), test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+
+			if !errors.Is(test.err, cause) {
+				t.Errorf("got: <%v>, want wrapped error: <%v>", test.err, cause)
+			}
+		})
+	}
+}
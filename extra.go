@@ -0,0 +1,261 @@
+package mfcg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// Generic coordinate nesting depths, from a single position to a slice of
+// polygons.
+const (
+	depthPoint = iota
+	depthPoints
+	depthRings
+	depthPolygons
+)
+
+// errCoordinateDepth is returned when coordinate data is not nested in one
+// of the ways supported by Geometry.
+var errCoordinateDepth = errors.New("expecting coordinate data to conform to a slice of Points nested at most 3 levels deep")
+
+// Feature is a feature of MFCG data whose ID is not recognized by this
+// package, such as a layer introduced by a newer version of MFCG. It is kept
+// so it can be written back out unchanged. A feature whose geometry cannot be
+// decoded, such as one holding 3D positions, is kept as Raw instead and its
+// Geometry is left empty.
+type Feature struct {
+	ID   string
+	NoID bool            // NoID is set if the feature has no "id" member, in which case none is written unless ID is set.
+	Raw  json.RawMessage // Raw is the feature as read if its geometry cannot be decoded. It is written back out as is.
+	Geometry
+}
+
+// Geometry is a generic geometry decoded without knowledge of its meaning.
+// Its Coordinates hold a Point, []Point, [][]Point or [][][]Point depending
+// on how deeply the coordinate data is nested. Members holds every other
+// member of the geometry (e.g. "width") as raw JSON.
+type Geometry struct {
+	Type        string
+	Coordinates interface{}
+	Geometries  []Geometry
+	Members     map[string]json.RawMessage
+}
+
+// UnmarshalJSON decodes a Feature from a JSON object.
+func (f *Feature) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	f.NoID = true
+	if raw, ok := members["id"]; ok {
+		if err := json.Unmarshal(raw, &f.ID); err != nil {
+			return err
+		}
+		f.NoID = false
+		delete(members, "id")
+	}
+
+	return f.Geometry.decode(members)
+}
+
+// MarshalJSON encodes the Feature as a JSON object.
+func (f Feature) MarshalJSON() ([]byte, error) {
+	if f.Raw != nil {
+		return f.Raw, nil
+	}
+
+	members := f.Geometry.encode()
+	if !f.NoID || f.ID != "" {
+		members["id"] = f.ID
+	}
+
+	return json.Marshal(members)
+}
+
+// UnmarshalJSON decodes a Geometry from a JSON object.
+func (g *Geometry) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	return g.decode(members)
+}
+
+// MarshalJSON encodes the Geometry as a JSON object.
+func (g Geometry) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.encode())
+}
+
+// decode sets the Geometry from the provided object members. A failure is
+// reported as a *ParseError locating the failing geometry and coordinates.
+func (g *Geometry) decode(members map[string]json.RawMessage) error {
+	if raw, ok := members["type"]; ok {
+		if err := json.Unmarshal(raw, &g.Type); err != nil {
+			return err
+		}
+		delete(members, "type")
+	}
+
+	if raw, ok := members["coordinates"]; ok {
+		coords, err := decodeCoordinates(raw)
+		if err != nil {
+			return err
+		}
+		g.Coordinates = coords
+		delete(members, "coordinates")
+	}
+
+	if raw, ok := members["geometries"]; ok {
+		var geos []json.RawMessage
+		if err := json.Unmarshal(raw, &geos); err != nil {
+			return err
+		}
+
+		g.Geometries = make([]Geometry, len(geos))
+		for i, geo := range geos {
+			if err := json.Unmarshal(geo, &g.Geometries[i]); err != nil {
+				pe := locate(err)
+				pe.Geometry = i
+				return pe
+			}
+		}
+		delete(members, "geometries")
+	}
+
+	if len(members) > 0 {
+		g.Members = members
+	}
+
+	return nil
+}
+
+// encode returns the members of the JSON object representing the Geometry.
+func (g Geometry) encode() map[string]interface{} {
+	members := make(map[string]interface{}, len(g.Members)+3)
+	for k, v := range g.Members {
+		members[k] = v
+	}
+
+	members["type"] = g.Type
+	if g.Coordinates != nil {
+		members["coordinates"] = g.Coordinates
+	}
+	if g.Geometries != nil {
+		members["geometries"] = g.Geometries
+	}
+
+	return members
+}
+
+// decodeCoordinates returns the generic coordinates corresponding to the
+// provided coordinate data, typed according to how deeply the data is
+// nested.
+func decodeCoordinates(data []byte) (interface{}, error) {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil, nil
+	}
+
+	switch coordinateDepth(data) {
+	case depthPoint:
+		var pt Point
+		if err := json.Unmarshal(data, &pt); err != nil {
+			pe := locate(err)
+			pe.Point = 0
+			return nil, pe
+		}
+		return pt, nil
+	case depthPoints:
+		return decodePoints(data)
+	case depthRings:
+		return decodeRings(data)
+	case depthPolygons:
+		var raws []json.RawMessage
+		if err := json.Unmarshal(data, &raws); err != nil {
+			return nil, err
+		}
+
+		polys := make([][][]Point, len(raws))
+		for i, raw := range raws {
+			rings, err := decodeRings(raw)
+			if err != nil {
+				pe := locate(err)
+				pe.Geometry = i
+				return nil, pe
+			}
+			polys[i] = rings
+		}
+		return polys, nil
+	}
+
+	return nil, errCoordinateDepth
+}
+
+// coordinateDepth returns how deeply the provided coordinate data is nested,
+// where a single position has a depth of depthPoint. An empty array is
+// assumed to hold positions.
+func coordinateDepth(data []byte) int {
+	data = bytes.TrimLeft(data, " \t\r\n")
+
+	brackets := 0
+	for len(data) > 0 && data[0] == '[' {
+		brackets++
+		data = bytes.TrimLeft(data[1:], " \t\r\n")
+	}
+
+	if len(data) > 0 && data[0] == ']' {
+		return brackets
+	}
+	return brackets - 1
+}
+
+// clone returns a deep copy of the Geometry.
+func (g Geometry) clone() Geometry {
+	switch c := g.Coordinates.(type) {
+	case []Point:
+		g.Coordinates = append([]Point(nil), c...)
+	case [][]Point:
+		g.Coordinates = cloneRings(c)
+	case [][][]Point:
+		polys := make([][][]Point, len(c))
+		for i, rings := range c {
+			polys[i] = cloneRings(rings)
+		}
+		g.Coordinates = polys
+	}
+
+	if g.Geometries != nil {
+		geos := make([]Geometry, len(g.Geometries))
+		for i, geo := range g.Geometries {
+			geos[i] = geo.clone()
+		}
+		g.Geometries = geos
+	}
+
+	if g.Members != nil {
+		members := make(map[string]json.RawMessage, len(g.Members))
+		for k, v := range g.Members {
+			members[k] = v
+		}
+		g.Members = members
+	}
+
+	return g
+}
+
+// cloneRings returns a deep copy of the provided rings.
+func cloneRings(rings [][]Point) [][]Point {
+	if rings == nil {
+		return nil
+	}
+
+	cp := make([][]Point, len(rings))
+	for i, ring := range rings {
+		cp[i] = append([]Point(nil), ring...)
+	}
+
+	return cp
+}
@@ -0,0 +1,269 @@
+package mfcg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+const testFileUnknown string = "./test_data/mapUnknownFeature.json"
+
+func TestNew_extra(t *testing.T) {
+	want := []Feature{
+		{
+			ID: "lanterns",
+			Geometry: Geometry{
+				Type:        "MultiPoint",
+				Coordinates: []Point{{X: 12.5, Y: -3.25}, {X: 14, Y: 7}},
+			},
+		},
+		{
+			ID: "banners",
+			Geometry: Geometry{
+				Type: "GeometryCollection",
+				Geometries: []Geometry{
+					{
+						Type:        "Polygon",
+						Coordinates: [][]Point{{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}}},
+						Members:     map[string]json.RawMessage{"name": json.RawMessage(`"Guild"`)},
+					},
+				},
+				Members: map[string]json.RawMessage{"color": json.RawMessage(`"red"`)},
+			},
+		},
+		{
+			NoID: true,
+			Geometry: Geometry{
+				Type:        "LineString",
+				Coordinates: []Point{{X: 5, Y: 5}, {X: 6, Y: 6}},
+			},
+		},
+	}
+
+	mp := testMap(t, testFileUnknown)
+	if diff := cmp.Diff(mp.Extra, want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(len(mp.Roads), 1); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestEncode_extra(t *testing.T) {
+	want := testMap(t, testFileUnknown)
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, want); err != nil {
+		t.Fatalf("got: <%v>, want error: <%v>", err, false)
+	}
+
+	got, err := New(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestNew_extraParseError(t *testing.T) {
+	banners := `{"type": "GeometryCollection", "id": "banners", "geometries": [
+			{"type": "Point", "coordinates": [1, 2]},
+			{"type": "MultiPolygon", "coordinates": [[[[1, 2]]], [[[3, 4], [5, "y"]]]]}
+		]}`
+	trees := `{"type": "MultiPoint", "id": "trees3d", "coordinates": [[1, 2, 3], [4, 5, 6]]}`
+	data := `{"features": [` + banners + `, ` + trees + `]}`
+
+	mp, err := New(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("got: <%v>, want error: <%v>", err, false)
+	}
+
+	want := []Feature{
+		{ID: "banners", Raw: json.RawMessage(banners)},
+		{ID: "trees3d", Raw: json.RawMessage(trees)},
+	}
+	if diff := cmp.Diff(mp.Extra, want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	if len(mp.Warnings) != 2 {
+		t.Fatalf("got: <%v>, want 2 warnings", mp.Warnings)
+	}
+
+	var got *ParseError
+	if !errors.As(mp.Warnings[0], &got) {
+		t.Fatalf("got: <%v>, want *ParseError", mp.Warnings[0])
+	}
+
+	wantErr := &ParseError{ID: "banners", Feature: 0, Geometry: 1, Ring: 0, Point: 1}
+	if diff := cmp.Diff(got, wantErr, cmpopts.IgnoreFields(ParseError{}, "Err")); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	if !errors.Is(mp.Warnings[0], ErrPointY) {
+		t.Errorf("got: <%v>, want wrapped error: <%v>", mp.Warnings[0], ErrPointY)
+	}
+
+	// Features kept as raw JSON are written back out unchanged.
+	var buf bytes.Buffer
+	if err := Encode(&buf, mp); err != nil {
+		t.Fatalf("got: <%v>, want error: <%v>", err, false)
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(trees)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), compact.String()) {
+		t.Errorf("got: <%s>, want to contain: <%s>", buf.String(), compact.String())
+	}
+}
+
+func TestFeature_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "With ID", data: `{"type": "Point", "id": "well", "coordinates": [1, 2]}`},
+		{name: "Without ID", data: `{"type": "Point", "coordinates": [1, 2]}`},
+		{name: "Empty ID", data: `{"type": "Point", "id": "", "coordinates": [1, 2]}`},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var ft Feature
+			if err := json.Unmarshal([]byte(test.data), &ft); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := json.Marshal(ft)
+			if err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+
+			var got, want interface{}
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(test.data), &want); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGeometry_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Geometry
+		wantErr bool
+	}{
+		{
+			name: "Point",
+			data: `{"type": "Point", "coordinates": [1, 2]}`,
+			want: Geometry{Type: "Point", Coordinates: Point{X: 1, Y: 2}},
+		},
+		{
+			name: "Empty LineString",
+			data: `{"type": "LineString", "coordinates": []}`,
+			want: Geometry{Type: "LineString", Coordinates: []Point{}},
+		},
+		{
+			name: "Polygon with width",
+			data: `{"type": "Polygon", "width": 7.6, "coordinates": [[[1, 2], [3, 4]]]}`,
+			want: Geometry{
+				Type:        "Polygon",
+				Coordinates: [][]Point{{{X: 1, Y: 2}, {X: 3, Y: 4}}},
+				Members:     map[string]json.RawMessage{"width": json.RawMessage(`7.6`)},
+			},
+		},
+		{
+			name: "MultiPolygon",
+			data: `{"type": "MultiPolygon", "coordinates": [[[[1, 2]]], [[]]]}`,
+			want: Geometry{
+				Type:        "MultiPolygon",
+				Coordinates: [][][]Point{{{{X: 1, Y: 2}}}, {{}}},
+			},
+		},
+		{
+			name:    "Too deeply nested",
+			data:    `{"type": "Unknown", "coordinates": [[[[[1, 2]]]]]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Not an object",
+			data:    `[1, 2]`,
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var got Geometry
+			err := json.Unmarshal([]byte(test.data), &got)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got: <%v>, want error: <%v>", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+
+			data, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+
+			var again, want interface{}
+			if err := json.Unmarshal(data, &again); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(test.data), &want); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(again, want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_coordinateDepth(t *testing.T) {
+	tests := []struct {
+		data string
+		want int
+	}{
+		{data: `[1, 2]`, want: depthPoint},
+		{data: ` [ [1, 2] ]`, want: depthPoints},
+		{data: `[]`, want: depthPoints},
+		{data: `[[[1, 2]]]`, want: depthRings},
+		{data: `[[]]`, want: depthRings},
+		{data: "[\n\t[[[1, 2]]]]", want: depthPolygons},
+		{data: `"foo"`, want: -1},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.data, func(t *testing.T) {
+			got := coordinateDepth([]byte(test.data))
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
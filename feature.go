@@ -4,17 +4,106 @@ import (
 	"encoding/json"
 )
 
-// featureCollection contains the list of a map's features.
+// Geometry types used by MFCG's features.
+const (
+	typeFeature            string = "Feature"
+	typeFeatureCollection  string = "FeatureCollection"
+	typeGeometryCollection string = "GeometryCollection"
+	typeLineString         string = "LineString"
+	typeMultiLineString    string = "MultiLineString"
+	typeMultiPoint         string = "MultiPoint"
+	typeMultiPolygon       string = "MultiPolygon"
+	typePoint              string = "Point"
+	typePolygon            string = "Polygon"
+)
+
+// featureCollection contains the list of a map's features. Each feature is
+// either a *feature or a Feature.
 type featureCollection struct {
-	Type     string     `json:"type"`
-	Features []*feature `json:"features"`
+	Type     string        `json:"type"`
+	Features []interface{} `json:"features"`
+}
+
+// rawFeatureCollection is a featureCollection whose features are yet to be
+// decoded.
+type rawFeatureCollection struct {
+	Type     string            `json:"type"`
+	Features []json.RawMessage `json:"features"`
 }
 
 // feature represents a specific type of map feature (e.g. roads, rivers, buildings).
 type feature struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
 	MetaData
-	Type        string          `json:"type"`
-	ID          string          `json:"id"`
-	Coordinates json.RawMessage `json:"coordinates"`
-	Geometries  json.RawMessage `json:"geometries"`
+	Coordinates json.RawMessage `json:"coordinates,omitempty"`
+	Geometries  json.RawMessage `json:"geometries,omitempty"`
+
+	index int // position within the collection
+}
+
+// locate returns err as a *ParseError located at the feature.
+func (ft feature) locate(err error) error {
+	pe := locate(err)
+	pe.ID = ft.ID
+	pe.Feature = ft.index
+
+	return pe
+}
+
+// merge returns a feature combining the geometries of ft with those of dup, a
+// later feature sharing ft's ID. Only features of layers exported as a
+// collection of geometries can be merged; any other duplicate results in an
+// error wrapping ErrDuplicateID located at dup.
+func (ft feature) merge(dup feature) (feature, error) {
+	l, _ := findLayer(ft.ID)
+	if ft.Type != dup.Type {
+		return ft, dup.locate(ErrDuplicateID)
+	}
+
+	var err error
+	switch l.typ {
+	case typeGeometryCollection:
+		ft.Geometries, err = concatArrays(ft.Geometries, dup.Geometries)
+	case typeMultiPoint, typeMultiPolygon:
+		ft.Coordinates, err = concatArrays(ft.Coordinates, dup.Coordinates)
+	default:
+		err = ErrDuplicateID
+	}
+	if err != nil {
+		return ft, dup.locate(err)
+	}
+
+	return ft, nil
+}
+
+// concatArrays returns a JSON array containing the elements of a followed by
+// the elements of b. Missing or null arrays are treated as empty.
+func concatArrays(a, b json.RawMessage) (json.RawMessage, error) {
+	var elems []json.RawMessage
+	for _, arr := range []json.RawMessage{a, b} {
+		if len(arr) == 0 {
+			continue
+		}
+
+		var part []json.RawMessage
+		if err := json.Unmarshal(arr, &part); err != nil {
+			return nil, err
+		}
+		elems = append(elems, part...)
+	}
+
+	if elems == nil {
+		return json.RawMessage("[]"), nil
+	}
+
+	return json.Marshal(elems)
+}
+
+// geometry represents a single member of one of MFCG's proprietary
+// GeometryCollections.
+type geometry struct {
+	Type        string      `json:"type"`
+	Width       float64     `json:"width"`
+	Coordinates interface{} `json:"coordinates"`
 }
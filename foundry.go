@@ -0,0 +1,195 @@
+package mfcg
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"io"
+	"math"
+)
+
+// Values of Foundry VTT's enumerations used by WriteFoundryScene.
+const (
+	foundryGridSquare  = 1
+	foundrySenseNormal = 20
+	foundryDoor        = 1
+	foundryDoorOpen    = 1
+	foundryNoteIcon    = "icons/svg/book.svg"
+	foundryMinGrid     = 50
+)
+
+// FoundryOptions describes how a Map is exported as a Foundry VTT scene.
+type FoundryOptions struct {
+	Name          string  // Name is the name of the scene.
+	Background    string  // Background is the path of the scene's background image, relative to Foundry's data directory.
+	PixelsPerUnit float64 // PixelsPerUnit is the scale of the scene in pixels per map unit. Defaults to 10.
+	GridSize      int     // GridSize is the size of a grid square in pixels. Foundry requires at least 50, so smaller sizes are raised to 50.
+	GridDistance  float64 // GridDistance is the distance spanned by a grid square in GridUnits. Defaults to 5.
+	GridUnits     string  // GridUnits is the unit of GridDistance.
+	Style         *Style  // Style is used by WriteFoundryBackground to render the background image. A nil Style is replaced by DefaultStyle.
+}
+
+// DefaultFoundryOptions returns FoundryOptions for a scene named "City"
+// whose grid squares span five map units, each counted as a foot.
+func DefaultFoundryOptions() *FoundryOptions {
+	return &FoundryOptions{
+		Name:          "City",
+		PixelsPerUnit: 10,
+		GridSize:      50,
+		GridDistance:  5,
+		GridUnits:     "ft",
+	}
+}
+
+// foundryScene is the JSON export of a Foundry VTT scene, as accepted by its
+// "Import Data" action. It follows the scene data of Foundry v10 and later,
+// which nests the background and grid and gives images as textures.
+// Positions are in pixels from the top left corner of the scene.
+type foundryScene struct {
+	Name       string         `json:"name"`
+	Width      int            `json:"width"`
+	Height     int            `json:"height"`
+	Padding    float64        `json:"padding"`
+	Background foundryTexture `json:"background"`
+	Grid       foundryGrid    `json:"grid"`
+	Walls      []foundryWall  `json:"walls"`
+	Notes      []foundryNote  `json:"notes"`
+}
+
+// foundryTexture refers to an image file.
+type foundryTexture struct {
+	Src string `json:"src,omitempty"`
+}
+
+// foundryGrid describes the grid of a foundryScene.
+type foundryGrid struct {
+	Type     int     `json:"type"`
+	Size     int     `json:"size"`
+	Distance float64 `json:"distance"`
+	Units    string  `json:"units"`
+}
+
+// foundryWall is a single wall segment from (C[0], C[1]) to (C[2], C[3]).
+// Its restrictions on movement, sight, light and sound are given as
+// Foundry's sense types.
+type foundryWall struct {
+	C     [4]float64 `json:"c"`
+	Move  int        `json:"move"`
+	Sight int        `json:"sight"`
+	Light int        `json:"light"`
+	Sound int        `json:"sound"`
+	Door  int        `json:"door"`
+	DS    int        `json:"ds"`
+}
+
+// foundryNote is a map note pinned to the scene.
+type foundryNote struct {
+	X        float64        `json:"x"`
+	Y        float64        `json:"y"`
+	Text     string         `json:"text"`
+	Texture  foundryTexture `json:"texture"`
+	IconSize int            `json:"iconSize"`
+}
+
+// WriteFoundryScene writes the Map to w as a scene for Foundry VTT v10 or
+// later using the provided FoundryOptions. A nil FoundryOptions is replaced
+// by DefaultFoundryOptions. The scene spans the Map's Bounds at the options'
+// scale and refers to the options' Background image, which
+// WriteFoundryBackground can render. Every segment of a building outline or
+// wall blocks movement and sight, each gate where a road crosses a wall is an
+// open door, and a note titled after its index is placed at the centroid of
+// each square.
+func (m *Map) WriteFoundryScene(w io.Writer, opts *FoundryOptions) error {
+	o, b, width, height := m.foundryLayout(opts)
+	project := func(pt Point) (float64, float64) {
+		return (pt.X - b.Min.X) * o.PixelsPerUnit, (pt.Y - b.Min.Y) * o.PixelsPerUnit
+	}
+
+	s := foundryScene{
+		Name:       o.Name,
+		Width:      width,
+		Height:     height,
+		Background: foundryTexture{Src: o.Background},
+		Grid:       foundryGrid{Type: foundryGridSquare, Size: o.GridSize, Distance: o.GridDistance, Units: o.GridUnits},
+		Walls:      []foundryWall{},
+		Notes:      []foundryNote{},
+	}
+
+	wall := func(u, v Point) foundryWall {
+		ux, uy := project(u)
+		vx, vy := project(v)
+		return foundryWall{
+			C:     [4]float64{ux, uy, vx, vy},
+			Move:  foundrySenseNormal,
+			Sight: foundrySenseNormal,
+			Light: foundrySenseNormal,
+			Sound: foundrySenseNormal,
+		}
+	}
+
+	walls, gates := m.wallLines()
+	for _, line := range append(m.buildingLines(), walls...) {
+		for i := 1; i < len(line); i++ {
+			if line[i-1] != line[i] {
+				s.Walls = append(s.Walls, wall(line[i-1], line[i]))
+			}
+		}
+	}
+	for _, g := range gates {
+		w := wall(g.a, g.b)
+		w.Door, w.DS = foundryDoor, foundryDoorOpen
+		s.Walls = append(s.Walls, w)
+	}
+
+	for i, p := range m.Squares {
+		x, y := project(p.Centroid())
+		s.Notes = append(s.Notes, foundryNote{
+			X:        x,
+			Y:        y,
+			Text:     fmt.Sprintf("Square %d", i+1),
+			Texture:  foundryTexture{Src: foundryNoteIcon},
+			IconSize: 40,
+		})
+	}
+
+	return json.NewEncoder(w).Encode(s)
+}
+
+// WriteFoundryBackground writes the Map to w as a PNG image matching the
+// size and scale of the scene written by WriteFoundryScene with the same
+// FoundryOptions, for use as its background. A nil FoundryOptions is
+// replaced by DefaultFoundryOptions.
+func (m *Map) WriteFoundryBackground(w io.Writer, opts *FoundryOptions) error {
+	o, b, width, height := m.foundryLayout(opts)
+
+	return png.Encode(w, m.rasterize(o.Style, b.Min, o.PixelsPerUnit, width, height))
+}
+
+// foundryLayout returns the provided FoundryOptions with defaults filled in,
+// along with the bounds of the Map covered by its scene and the size of the
+// scene in pixels.
+func (m *Map) foundryLayout(opts *FoundryOptions) (FoundryOptions, Rect, int, int) {
+	def := DefaultFoundryOptions()
+	if opts == nil {
+		opts = def
+	}
+	o := *opts
+	if o.PixelsPerUnit <= 0 {
+		o.PixelsPerUnit = def.PixelsPerUnit
+	}
+	if o.GridSize < foundryMinGrid {
+		o.GridSize = foundryMinGrid
+	}
+	if o.GridDistance <= 0 {
+		o.GridDistance = def.GridDistance
+	}
+
+	b := m.Bounds()
+	if b.Empty() {
+		b = Rect{}
+	}
+	width := int(math.Max(1, math.Ceil(b.Dx()*o.PixelsPerUnit)))
+	height := int(math.Max(1, math.Ceil(b.Dy()*o.PixelsPerUnit)))
+
+	return o, b, width, height
+}
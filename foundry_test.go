@@ -0,0 +1,154 @@
+package mfcg
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/png"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMap_WriteFoundryScene(t *testing.T) {
+	mp := &Map{
+		Buildings: []Polygon{square(20, 20, 10)},
+		Roads:     []LineString{{Width: 6, Coords: []Point{{X: 50, Y: -20}, {X: 50, Y: 50}}}},
+		Squares:   []Polygon{square(60, 60, 20)},
+		Walls:     []Polygon{{Coords: [][]Point{{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 100}, {X: 0, Y: 100}}}}},
+	}
+
+	tests := []struct {
+		name      string
+		opts      *FoundryOptions
+		wantScene foundryScene
+		wantWalls int
+	}{
+		{
+			name: "Default options",
+			opts: nil,
+			wantScene: foundryScene{
+				Name:   "City",
+				Width:  1000,
+				Height: 1200,
+				Grid:   foundryGrid{Type: foundryGridSquare, Size: 50, Distance: 5, Units: "ft"},
+				Notes:  []foundryNote{{X: 700, Y: 900, Text: "Square 1", Texture: foundryTexture{Src: foundryNoteIcon}, IconSize: 40}},
+			},
+			wantWalls: 4 + 5,
+		},
+		{
+			name: "Custom options",
+			opts: &FoundryOptions{Name: "Town", Background: "maps/town.png", PixelsPerUnit: 2, GridSize: 100, GridDistance: 1.5, GridUnits: "m"},
+			wantScene: foundryScene{
+				Name:       "Town",
+				Width:      200,
+				Height:     240,
+				Background: foundryTexture{Src: "maps/town.png"},
+				Grid:       foundryGrid{Type: foundryGridSquare, Size: 100, Distance: 1.5, Units: "m"},
+				Notes:      []foundryNote{{X: 140, Y: 180, Text: "Square 1", Texture: foundryTexture{Src: foundryNoteIcon}, IconSize: 40}},
+			},
+			wantWalls: 4 + 5,
+		},
+		{
+			name: "Grid too small",
+			opts: &FoundryOptions{Name: "Town", GridSize: 20},
+			wantScene: foundryScene{
+				Name:   "Town",
+				Width:  1000,
+				Height: 1200,
+				Grid:   foundryGrid{Type: foundryGridSquare, Size: 50, Distance: 5},
+				Notes:  []foundryNote{{X: 700, Y: 900, Text: "Square 1", Texture: foundryTexture{Src: foundryNoteIcon}, IconSize: 40}},
+			},
+			wantWalls: 4 + 5,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var scene bytes.Buffer
+			if err := mp.WriteFoundryScene(&scene, test.opts); err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+
+			var got foundryScene
+			if err := json.Unmarshal(scene.Bytes(), &got); err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+
+			// Foundry v10 and later give the note's icon as a texture.
+			var notes struct {
+				Notes []map[string]json.RawMessage `json:"notes"`
+			}
+			if err := json.Unmarshal(scene.Bytes(), &notes); err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+			for _, n := range notes.Notes {
+				if diff := cmp.Diff(string(n["texture"]), `{"src":"icons/svg/book.svg"}`); diff != "" {
+					t.Errorf("mismatch (-got +want):\n%s", diff)
+				}
+			}
+
+			var walls, doors []foundryWall
+			for _, w := range got.Walls {
+				if w.Door == foundryDoor {
+					doors = append(doors, w)
+					continue
+				}
+				walls = append(walls, w)
+			}
+			got.Walls = nil
+
+			if diff := cmp.Diff(got, test.wantScene); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+			if diff := cmp.Diff(len(walls), test.wantWalls); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+
+			// The road crosses the wall through a gate as wide as the road.
+			k := float64(test.wantScene.Width) / 100
+			wantDoor := []foundryWall{{
+				C:     [4]float64{47 * k, 20 * k, 53 * k, 20 * k},
+				Move:  foundrySenseNormal,
+				Sight: foundrySenseNormal,
+				Light: foundrySenseNormal,
+				Sound: foundrySenseNormal,
+				Door:  foundryDoor,
+				DS:    foundryDoorOpen,
+			}}
+			if diff := cmp.Diff(doors, wantDoor); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMap_WriteFoundryBackground(t *testing.T) {
+	mp := &Map{
+		Buildings: []Polygon{square(20, 20, 10)},
+		Walls:     []Polygon{{Coords: [][]Point{{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 100}, {X: 0, Y: 100}}}}},
+	}
+
+	for _, opts := range []*FoundryOptions{nil, {PixelsPerUnit: 2}} {
+		var scene, img bytes.Buffer
+		if err := mp.WriteFoundryScene(&scene, opts); err != nil {
+			t.Fatalf("got: <%v>, want error: <%v>", err, false)
+		}
+		if err := mp.WriteFoundryBackground(&img, opts); err != nil {
+			t.Fatalf("got: <%v>, want error: <%v>", err, false)
+		}
+
+		var got foundryScene
+		if err := json.Unmarshal(scene.Bytes(), &got); err != nil {
+			t.Fatalf("got: <%v>, want error: <%v>", err, false)
+		}
+		cfg, err := png.DecodeConfig(&img)
+		if err != nil {
+			t.Fatalf("got: <%v>, want error: <%v>", err, false)
+		}
+
+		if diff := cmp.Diff([2]int{cfg.Width, cfg.Height}, [2]int{got.Width, got.Height}); diff != "" {
+			t.Errorf("mismatch (-got +want):\n%s", diff)
+		}
+	}
+}
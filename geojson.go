@@ -0,0 +1,302 @@
+package mfcg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// geoJSONCollection is an RFC 7946 FeatureCollection. The MetaData of the
+// map is kept in the foreign "values" member.
+type geoJSONCollection struct {
+	Type     string           `json:"type"`
+	Values   MetaData         `json:"values"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+// geoJSONFeature is an RFC 7946 Feature representing a single building,
+// road, wall, etc. Geometry is a geoJSONGeometry, a Geometry, raw JSON
+// members or nil.
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	Geometry   interface{}       `json:"geometry"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+// geoJSONGeometry is an RFC 7946 Geometry object.
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// geoJSONProperties contains the properties of a geoJSONFeature. Layer
+// matches the ID of the MFCG feature the geometry originates from. Members
+// holds the members of an Extra Feature-typed feature, which are written as
+// further properties.
+type geoJSONProperties struct {
+	Layer   string                     `json:"layer"`
+	Name    string                     `json:"name,omitempty"`
+	Width   float64                    `json:"width,omitempty"`
+	Members map[string]json.RawMessage `json:"-"`
+}
+
+// MarshalJSON encodes the properties as a JSON object.
+func (p geoJSONProperties) MarshalJSON() ([]byte, error) {
+	members := make(map[string]interface{}, len(p.Members)+3)
+	for k, v := range p.Members {
+		members[k] = v
+	}
+
+	members["layer"] = p.Layer
+	if p.Name != "" {
+		members["name"] = p.Name
+	}
+	if p.Width != 0 {
+		members["width"] = p.Width
+	}
+
+	return json.Marshal(members)
+}
+
+// WriteGeoJSON writes the Map to w as an RFC 7946 GeoJSON FeatureCollection.
+// Every building, road, wall, etc. is written as its own Feature whose
+// "layer" property names the MFCG feature it belongs to. Each of the Map's
+// Extra features is written as a single Feature keeping its geometry's
+// members, except for Feature-typed extras whose members become properties.
+// Open walls are written as MultiLineStrings. Polygon rings are closed and
+// wound as RFC 7946 requires: exteriors counterclockwise and holes
+// clockwise, with Y pointing up. The MetaData of the Map is written to the
+// collection's foreign "values" member. An error is returned if a Raw Extra
+// feature is not a valid JSON object.
+func (m *Map) WriteGeoJSON(w io.Writer) error {
+	collect := geoJSONCollection{
+		Type:     typeFeatureCollection,
+		Values:   m.MetaData,
+		Features: []geoJSONFeature{},
+	}
+
+	if m.Earth.Coords != nil {
+		collect.Features = append(collect.Features, polygonToGeoJSON(IDEarth, m.Earth))
+	}
+
+	collect.Features = appendLineStringsGeoJSON(collect.Features, IDPlanks, m.Planks)
+	collect.Features = appendLineStringsGeoJSON(collect.Features, IDRivers, m.Rivers)
+	collect.Features = appendLineStringsGeoJSON(collect.Features, IDRoads, m.Roads)
+	collect.Features = appendPolygonsGeoJSON(collect.Features, IDBuildings, m.Buildings)
+	collect.Features = appendPolygonsGeoJSON(collect.Features, IDFields, m.Fields)
+	collect.Features = appendPolygonsGeoJSON(collect.Features, IDGreens, m.Greens)
+	collect.Features = appendPolygonsGeoJSON(collect.Features, IDPrisms, m.Prisms)
+	collect.Features = appendPolygonsGeoJSON(collect.Features, IDSquares, m.Squares)
+	if m.OpenWalls {
+		collect.Features = appendPathsGeoJSON(collect.Features, IDWalls, m.Walls)
+	} else {
+		collect.Features = appendPolygonsGeoJSON(collect.Features, IDWalls, m.Walls)
+	}
+	collect.Features = appendPolygonsGeoJSON(collect.Features, IDWater, m.Water)
+	collect.Features = appendPointsGeoJSON(collect.Features, IDTrees, m.Trees)
+
+	for _, d := range m.Districts {
+		ft := polygonToGeoJSON(IDDistricts, d.Polygon)
+		ft.Properties.Name = d.Name
+		collect.Features = append(collect.Features, ft)
+	}
+
+	for _, ex := range m.Extra {
+		ft, err := extraToGeoJSON(ex)
+		if err != nil {
+			return err
+		}
+		collect.Features = append(collect.Features, ft)
+	}
+
+	return json.NewEncoder(w).Encode(collect)
+}
+
+// extraToGeoJSON returns the Feature representing the provided Extra feature.
+// A geometry is kept along with its members, and Raw geometries are kept as
+// read. A Feature-typed extra keeps its "geometry" member, if any, while its
+// other members become properties. An error is returned if a Raw extra is
+// not a JSON object or its type is not a string.
+func extraToGeoJSON(ex Feature) (geoJSONFeature, error) {
+	ft := geoJSONFeature{
+		Type:       typeFeature,
+		Properties: geoJSONProperties{Layer: ex.ID},
+	}
+
+	var typ string
+	members := make(map[string]json.RawMessage)
+	switch {
+	case ex.Raw != nil:
+		if err := json.Unmarshal(ex.Raw, &members); err != nil {
+			return ft, fmt.Errorf("extra feature %q: %w", ex.ID, err)
+		}
+		if data, ok := members["type"]; ok {
+			if err := json.Unmarshal(data, &typ); err != nil {
+				return ft, fmt.Errorf("extra feature %q: type: %w", ex.ID, err)
+			}
+		}
+		delete(members, "id")
+	case ex.Type == typeFeature:
+		typ = typeFeature
+		for k, v := range ex.Members {
+			members[k] = v
+		}
+	default:
+		ft.Geometry = orientGeometry(ex.Geometry)
+		return ft, nil
+	}
+
+	if typ != typeFeature {
+		ft.Geometry = members
+		return ft, nil
+	}
+
+	delete(members, "type")
+	if geo, ok := members["geometry"]; ok {
+		ft.Geometry = geo
+		delete(members, "geometry")
+	}
+	if len(members) > 0 {
+		ft.Properties.Members = members
+	}
+
+	return ft, nil
+}
+
+// appendLineStringsGeoJSON appends a LineString Feature for each of the
+// provided LineStrings to feats.
+func appendLineStringsGeoJSON(feats []geoJSONFeature, layer string, lines []LineString) []geoJSONFeature {
+	for _, ln := range lines {
+		coords := ln.Coords
+		if coords == nil {
+			coords = []Point{}
+		}
+
+		feats = append(feats, geoJSONFeature{
+			Type: typeFeature,
+			Geometry: geoJSONGeometry{
+				Type:        typeLineString,
+				Coordinates: coords,
+			},
+			Properties: geoJSONProperties{Layer: layer, Width: ln.Width},
+		})
+	}
+
+	return feats
+}
+
+// appendPointsGeoJSON appends a Point Feature for each of the provided
+// Points to feats.
+func appendPointsGeoJSON(feats []geoJSONFeature, layer string, pts []Point) []geoJSONFeature {
+	for _, pt := range pts {
+		feats = append(feats, geoJSONFeature{
+			Type: typeFeature,
+			Geometry: geoJSONGeometry{
+				Type:        typePoint,
+				Coordinates: pt,
+			},
+			Properties: geoJSONProperties{Layer: layer},
+		})
+	}
+
+	return feats
+}
+
+// appendPolygonsGeoJSON appends a Polygon Feature for each of the provided
+// Polygons to feats.
+func appendPolygonsGeoJSON(feats []geoJSONFeature, layer string, polys []Polygon) []geoJSONFeature {
+	for _, p := range polys {
+		feats = append(feats, polygonToGeoJSON(layer, p))
+	}
+
+	return feats
+}
+
+// appendPathsGeoJSON appends a MultiLineString Feature for each of the
+// provided Polygons to feats. The rings of the Polygons are written as is.
+func appendPathsGeoJSON(feats []geoJSONFeature, layer string, polys []Polygon) []geoJSONFeature {
+	for _, p := range polys {
+		paths := p.Coords
+		if paths == nil {
+			paths = [][]Point{}
+		}
+
+		feats = append(feats, geoJSONFeature{
+			Type: typeFeature,
+			Geometry: geoJSONGeometry{
+				Type:        typeMultiLineString,
+				Coordinates: paths,
+			},
+			Properties: geoJSONProperties{Layer: layer, Width: p.Width},
+		})
+	}
+
+	return feats
+}
+
+// polygonToGeoJSON returns a Polygon Feature corresponding to the provided
+// Polygon, with its rings oriented by geoJSONRings.
+func polygonToGeoJSON(layer string, p Polygon) geoJSONFeature {
+	return geoJSONFeature{
+		Type: typeFeature,
+		Geometry: geoJSONGeometry{
+			Type:        typePolygon,
+			Coordinates: geoJSONRings(p.Coords),
+		},
+		Properties: geoJSONProperties{Layer: layer, Width: p.Width},
+	}
+}
+
+// geoJSONRings returns a copy of the provided rings of a Polygon as RFC 7946
+// requires them. Each ring is closed if MFCG left it open, the exterior ring
+// is wound counterclockwise and holes clockwise.
+func geoJSONRings(rings [][]Point) [][]Point {
+	oriented := make([][]Point, len(rings))
+	for i, ring := range rings {
+		oriented[i] = closeRing(orientedRing(ring, i == 0))
+	}
+
+	return oriented
+}
+
+// closeRing returns a copy of the provided ring whose last Point equals its
+// first Point.
+func closeRing(ring []Point) []Point {
+	closed := make([]Point, len(ring), len(ring)+1)
+	copy(closed, ring)
+
+	if len(ring) > 0 && ring[0] != ring[len(ring)-1] {
+		closed = append(closed, ring[0])
+	}
+
+	return closed
+}
+
+// orientGeometry returns a copy of the provided Geometry whose polygon rings
+// are oriented by geoJSONRings.
+func orientGeometry(g Geometry) Geometry {
+	switch g.Type {
+	case typePolygon:
+		if rings, ok := g.Coordinates.([][]Point); ok {
+			g.Coordinates = geoJSONRings(rings)
+		}
+	case typeMultiPolygon:
+		if polys, ok := g.Coordinates.([][][]Point); ok {
+			oriented := make([][][]Point, len(polys))
+			for i, rings := range polys {
+				oriented[i] = geoJSONRings(rings)
+			}
+			g.Coordinates = oriented
+		}
+	}
+
+	if g.Geometries != nil {
+		geos := make([]Geometry, len(g.Geometries))
+		for i, geo := range g.Geometries {
+			geos[i] = orientGeometry(geo)
+		}
+		g.Geometries = geos
+	}
+
+	return g
+}
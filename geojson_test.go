@@ -0,0 +1,292 @@
+package mfcg
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMap_WriteGeoJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		mp   *Map
+		want string
+	}{
+		{
+			name: "Empty map",
+			mp:   &Map{},
+			want: `{"type": "FeatureCollection", "values": {}, "features": []}`,
+		},
+		{
+			name: "Single layer per geometry",
+			mp: &Map{
+				MetaData: MetaData{RoadWidth: 8, Generator: "mfcg"},
+				Earth:    Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}}}},
+				Roads:    []LineString{{Width: 8, Coords: []Point{{X: 1, Y: 2}, {X: 3, Y: 4}}}},
+				Walls:    []Polygon{{Width: 7.6, Coords: [][]Point{{{X: 5, Y: 5}, {X: 6, Y: 5}, {X: 6, Y: 6}, {X: 5, Y: 5}}}}},
+				Trees:    []Point{{X: 9, Y: 8}},
+				Districts: []District{
+					{Name: "Docks", Polygon: Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}}}}},
+				},
+			},
+			want: `{
+				"type": "FeatureCollection",
+				"values": {"roadWidth": 8, "generator": "mfcg"},
+				"features": [
+					{
+						"type": "Feature",
+						"geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]},
+						"properties": {"layer": "earth"}
+					},
+					{
+						"type": "Feature",
+						"geometry": {"type": "LineString", "coordinates": [[1, 2], [3, 4]]},
+						"properties": {"layer": "roads", "width": 8}
+					},
+					{
+						"type": "Feature",
+						"geometry": {"type": "Polygon", "coordinates": [[[5, 5], [6, 5], [6, 6], [5, 5]]]},
+						"properties": {"layer": "walls", "width": 7.6}
+					},
+					{
+						"type": "Feature",
+						"geometry": {"type": "Point", "coordinates": [9, 8]},
+						"properties": {"layer": "trees"}
+					},
+					{
+						"type": "Feature",
+						"geometry": {"type": "Polygon", "coordinates": [[[0, 0], [2, 0], [2, 2], [0, 0]]]},
+						"properties": {"layer": "districts", "name": "Docks"}
+					}
+				]
+			}`,
+		},
+		{
+			name: "Rings oriented",
+			mp: &Map{
+				Buildings: []Polygon{{Coords: [][]Point{
+					{{X: 0, Y: 0}, {X: 0, Y: 4}, {X: 4, Y: 4}, {X: 4, Y: 0}},
+					{{X: 1, Y: 1}, {X: 3, Y: 1}, {X: 3, Y: 3}, {X: 1, Y: 3}},
+				}}},
+			},
+			want: `{
+				"type": "FeatureCollection",
+				"values": {},
+				"features": [
+					{
+						"type": "Feature",
+						"geometry": {"type": "Polygon", "coordinates": [
+							[[4, 0], [4, 4], [0, 4], [0, 0], [4, 0]],
+							[[1, 3], [3, 3], [3, 1], [1, 1], [1, 3]]
+						]},
+						"properties": {"layer": "buildings"}
+					}
+				]
+			}`,
+		},
+		{
+			name: "Open walls",
+			mp: &Map{
+				Walls:     []Polygon{{Width: 5, Coords: [][]Point{{{X: 0, Y: -35}, {X: 0, Y: 0}, {X: -35, Y: 0}}}}},
+				OpenWalls: true,
+			},
+			want: `{
+				"type": "FeatureCollection",
+				"values": {},
+				"features": [
+					{
+						"type": "Feature",
+						"geometry": {"type": "MultiLineString", "coordinates": [[[0, -35], [0, 0], [-35, 0]]]},
+						"properties": {"layer": "walls", "width": 5}
+					}
+				]
+			}`,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := test.mp.WriteGeoJSON(&buf); err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+
+			var got, want interface{}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(test.want), &want); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMap_WriteGeoJSON_layers(t *testing.T) {
+	mp := testMap(t, testFileMap)
+
+	var buf bytes.Buffer
+	if err := mp.WriteGeoJSON(&buf); err != nil {
+		t.Fatalf("got: <%v>, want error: <%v>", err, false)
+	}
+
+	var got geoJSONCollection
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	layers := make(map[string]int)
+	for _, ft := range got.Features {
+		layers[ft.Properties.Layer]++
+	}
+
+	want := map[string]int{
+		IDEarth:     1,
+		IDPlanks:    1,
+		IDRivers:    1,
+		IDRoads:     2,
+		IDBuildings: 2,
+		IDFields:    2,
+		IDPrisms:    1,
+		IDSquares:   1,
+		IDWalls:     1,
+		IDWater:     1,
+	}
+	if diff := cmp.Diff(layers, want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(got.Values, mp.MetaData); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestMap_WriteGeoJSON_extra(t *testing.T) {
+	mp := &Map{
+		Extra: []Feature{
+			{
+				ID: "banners",
+				Geometry: Geometry{
+					Type:        "MultiPolygon",
+					Coordinates: [][][]Point{{{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}}}},
+					Members:     map[string]json.RawMessage{"width": json.RawMessage(`3`)},
+				},
+			},
+			{
+				ID: "weather",
+				Geometry: Geometry{
+					Type:    "Feature",
+					Members: map[string]json.RawMessage{"season": json.RawMessage(`"winter"`)},
+				},
+			},
+			{
+				ID:  "trees3d",
+				Raw: json.RawMessage(`{"type": "MultiPoint", "id": "trees3d", "coordinates": [[1, 2, 3]]}`),
+			},
+		},
+	}
+	want := `{
+		"type": "FeatureCollection",
+		"values": {},
+		"features": [
+			{
+				"type": "Feature",
+				"geometry": {"type": "MultiPolygon", "width": 3, "coordinates": [[[[1, 1], [2, 1], [2, 2], [1, 1]]]]},
+				"properties": {"layer": "banners"}
+			},
+			{
+				"type": "Feature",
+				"geometry": null,
+				"properties": {"layer": "weather", "season": "winter"}
+			},
+			{
+				"type": "Feature",
+				"geometry": {"type": "MultiPoint", "coordinates": [[1, 2, 3]]},
+				"properties": {"layer": "trees3d"}
+			}
+		]
+	}`
+
+	var buf bytes.Buffer
+	if err := mp.WriteGeoJSON(&buf); err != nil {
+		t.Fatalf("got: <%v>, want error: <%v>", err, false)
+	}
+
+	var got, wantJSON interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(got, wantJSON); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestMap_WriteGeoJSON_extraError(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  json.RawMessage
+	}{
+		{
+			name: "Not an object",
+			raw:  json.RawMessage(`[1, 2]`),
+		},
+		{
+			name: "Type not a string",
+			raw:  json.RawMessage(`{"type": 3, "id": "lanterns"}`),
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			mp := &Map{Extra: []Feature{{ID: "lanterns", Raw: test.raw}}}
+
+			var buf bytes.Buffer
+			if err := mp.WriteGeoJSON(&buf); err == nil {
+				t.Errorf("got: <%v>, want error: <%v>", err, true)
+			}
+		})
+	}
+}
+
+func Test_closeRing(t *testing.T) {
+	tests := []struct {
+		name string
+		ring []Point
+		want []Point
+	}{
+		{
+			name: "Open ring",
+			ring: []Point{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}},
+			want: []Point{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 1, Y: 1}},
+		},
+		{
+			name: "Closed ring",
+			ring: []Point{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 1, Y: 1}},
+			want: []Point{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 1, Y: 1}},
+		},
+		{
+			name: "Empty ring",
+			ring: nil,
+			want: []Point{},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := closeRing(test.ring)
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package mfcg
+
+import "math"
+
+// earthRadius is the radius in meters of the sphere used by Web Mercator.
+const earthRadius = 6378137
+
+// GeoReference places a Map on the surface of the Earth. The Map's origin is
+// anchored at the provided latitude and longitude, in degrees, with north
+// pointing up the Map. Distances are projected through a Web Mercator plane
+// tangent to the anchor, so the Map keeps its shape and scale around it.
+type GeoReference struct {
+	Lat           float64 // Lat is the latitude of the Map's origin in degrees.
+	Lon           float64 // Lon is the longitude of the Map's origin in degrees.
+	MetersPerUnit float64 // MetersPerUnit is the length in meters of one MFCG unit. Zero is treated as 1.
+	Rotation      float64 // Rotation turns the Map clockwise about its origin, in degrees.
+}
+
+// Project returns the WGS84 position of Point pt of a Map, with its
+// longitude as X and its latitude as Y.
+func (g GeoReference) Project(pt Point) Point {
+	lat0 := g.Lat * math.Pi / 180
+	mx0 := earthRadius * g.Lon * math.Pi / 180
+	my0 := earthRadius * math.Log(math.Tan(math.Pi/4+lat0/2))
+
+	// MFCG's y-down units become meters east and north of the anchor, which
+	// Web Mercator stretches by the secant of the anchor's latitude.
+	local := Rotate(-g.Rotation * math.Pi / 180).Mul(FlipY()).Apply(pt)
+	k := g.metersPerUnit() / math.Cos(lat0)
+	mx, my := mx0+local.X*k, my0+local.Y*k
+
+	return Point{
+		X: mx / earthRadius * 180 / math.Pi,
+		Y: (2*math.Atan(math.Exp(my/earthRadius)) - math.Pi/2) * 180 / math.Pi,
+	}
+}
+
+// metersPerUnit returns the GeoReference's MetersPerUnit, defaulting to 1.
+func (g GeoReference) metersPerUnit() float64 {
+	if g.MetersPerUnit == 0 {
+		return 1
+	}
+	return g.MetersPerUnit
+}
+
+// Georeference projects every Point of the Map in place onto WGS84
+// longitudes and latitudes using the provided GeoReference, so the Map can be
+// written with WriteGeoJSON and displayed on a web map. Widths and the
+// MetaData's road width, river width, wall thickness and tower radius are
+// converted to meters.
+func (m *Map) Georeference(g GeoReference) {
+	m.mapPoints(g.Project)
+	m.scaleWidths(g.metersPerUnit())
+}
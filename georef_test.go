@@ -0,0 +1,87 @@
+package mfcg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestGeoReference_Project(t *testing.T) {
+	// deg is the angle in degrees spanned by 1000 meters along the equator.
+	deg := 1000.0 / earthRadius * 180 / math.Pi
+
+	tests := []struct {
+		name string
+		g    GeoReference
+		pt   Point
+		want Point
+	}{
+		{
+			name: "Anchor",
+			g:    GeoReference{Lat: 48.8566, Lon: 2.3522, MetersPerUnit: 2},
+			pt:   Point{},
+			want: Point{X: 2.3522, Y: 48.8566},
+		},
+		{
+			name: "East at the equator",
+			g:    GeoReference{},
+			pt:   Point{X: 1000, Y: 0},
+			want: Point{X: deg, Y: 0},
+		},
+		{
+			name: "North is up",
+			g:    GeoReference{MetersPerUnit: 10},
+			pt:   Point{X: 0, Y: -100},
+			want: Point{X: 0, Y: deg},
+		},
+		{
+			name: "East at 60 degrees north",
+			g:    GeoReference{Lat: 60},
+			pt:   Point{X: 1000, Y: 0},
+			want: Point{X: 2 * deg, Y: 60},
+		},
+		{
+			name: "Rotated clockwise",
+			g:    GeoReference{Rotation: 90},
+			pt:   Point{X: 1000, Y: 0},
+			want: Point{X: 0, Y: -deg},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := test.g.Project(test.pt)
+			if diff := cmp.Diff(got, test.want, cmpopts.EquateApprox(0, 1e-6)); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMap_Georeference(t *testing.T) {
+	g := GeoReference{Lat: 51.5, Lon: -0.12, MetersPerUnit: 0.5}
+	mp := &Map{
+		MetaData: MetaData{RoadWidth: 8, WallThickness: 7.6},
+		Roads:    []LineString{{Width: 8, Coords: []Point{{X: 0, Y: 0}, {X: 100, Y: 50}}}},
+	}
+
+	want := &Map{
+		MetaData: MetaData{RoadWidth: 4, WallThickness: 3.8},
+		Roads:    []LineString{{Width: 4, Coords: []Point{g.Project(Point{X: 0, Y: 0}), g.Project(Point{X: 100, Y: 50})}}},
+	}
+
+	mp.Georeference(g)
+
+	if diff := cmp.Diff(mp, want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	// The second vertex lies 50 meters east and 25 meters south of the anchor.
+	end := mp.Roads[0].Coords[1]
+	if end.X <= g.Lon || end.Y >= g.Lat {
+		t.Errorf("got: <%v>, want: <%s>", end, "south-east of anchor")
+	}
+}
@@ -0,0 +1,226 @@
+package mfcg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"image/color"
+	"io"
+	"math"
+)
+
+// Constants of the binary glTF 2.0 container and of the glTF enumerations
+// used by WriteGLB.
+const (
+	glbMagic     uint32 = 0x46546c67 // "glTF"
+	glbVersion   uint32 = 2
+	glbChunkJSON uint32 = 0x4e4f534a // "JSON"
+	glbChunkBIN  uint32 = 0x004e4942 // "BIN\x00"
+
+	gltfFloat        = 5126
+	gltfUnsignedInt  = 5125
+	gltfArrayBuffer  = 34962
+	gltfElementArray = 34963
+	gltfTriangles    = 4
+)
+
+// gltfDocument is the JSON chunk of a glTF 2.0 asset. glTF forbids empty
+// arrays, so arrays of a model without meshes are left out.
+type gltfDocument struct {
+	Asset       gltfAsset        `json:"asset"`
+	Scene       int              `json:"scene"`
+	Scenes      []gltfScene      `json:"scenes"`
+	Nodes       []gltfNode       `json:"nodes,omitempty"`
+	Meshes      []gltfMesh       `json:"meshes,omitempty"`
+	Materials   []gltfMaterial   `json:"materials,omitempty"`
+	Accessors   []gltfAccessor   `json:"accessors,omitempty"`
+	BufferViews []gltfBufferView `json:"bufferViews,omitempty"`
+	Buffers     []gltfBuffer     `json:"buffers,omitempty"`
+}
+
+// gltfAsset describes the glTF version of an asset and its producer.
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator"`
+}
+
+// gltfScene lists the root nodes of a scene.
+type gltfScene struct {
+	Nodes []int `json:"nodes,omitempty"`
+}
+
+// gltfNode places a mesh in a scene.
+type gltfNode struct {
+	Name string `json:"name"`
+	Mesh int    `json:"mesh"`
+}
+
+// gltfMesh is the geometry of a single layer.
+type gltfMesh struct {
+	Name       string          `json:"name"`
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+// gltfPrimitive is a set of triangles drawn with a single material.
+// Attributes and Indices refer to accessors.
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    int            `json:"indices"`
+	Material   int            `json:"material"`
+	Mode       int            `json:"mode"`
+}
+
+// gltfMaterial describes the appearance of a primitive.
+type gltfMaterial struct {
+	Name      string  `json:"name"`
+	PBR       gltfPBR `json:"pbrMetallicRoughness"`
+	AlphaMode string  `json:"alphaMode,omitempty"`
+}
+
+// gltfPBR holds the metallic-roughness parameters of a gltfMaterial.
+type gltfPBR struct {
+	BaseColorFactor [4]float64 `json:"baseColorFactor"`
+	MetallicFactor  float64    `json:"metallicFactor"`
+	RoughnessFactor float64    `json:"roughnessFactor"`
+}
+
+// gltfAccessor describes how to read typed values from a buffer view.
+type gltfAccessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float64 `json:"min,omitempty"`
+	Max           []float64 `json:"max,omitempty"`
+}
+
+// gltfBufferView is a slice of a buffer.
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target"`
+}
+
+// gltfBuffer is the binary chunk of a GLB file.
+type gltfBuffer struct {
+	ByteLength int `json:"byteLength"`
+}
+
+// WriteGLB writes the Map to w as a binary glTF 2.0 model extruded using the
+// provided ModelOptions. A nil ModelOptions is replaced by
+// DefaultModelOptions. The model holds one node, mesh and material per
+// layer, all named after the layer's ID. Its y-axis points up and one unit
+// is one map unit.
+func (m *Map) WriteGLB(w io.Writer, opts *ModelOptions) error {
+	md := m.model(opts)
+
+	doc := gltfDocument{
+		Asset:  gltfAsset{Version: "2.0", Generator: "mfcg"},
+		Scenes: []gltfScene{{Nodes: []int{}}},
+	}
+	var bin bytes.Buffer
+	for i, mesh := range md.meshes {
+		lo := []float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+		hi := []float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+		positions := bin.Len()
+		for _, v := range mesh.vertices {
+			for k, f := range v {
+				// Bounds are taken after rounding, as glTF requires them to
+				// match the stored values.
+				f32 := float32(f)
+				lo[k] = math.Min(lo[k], float64(f32))
+				hi[k] = math.Max(hi[k], float64(f32))
+				binary.Write(&bin, binary.LittleEndian, f32)
+			}
+		}
+		indices := bin.Len()
+		for _, n := range mesh.indices {
+			binary.Write(&bin, binary.LittleEndian, uint32(n))
+		}
+
+		doc.BufferViews = append(doc.BufferViews,
+			gltfBufferView{ByteOffset: positions, ByteLength: indices - positions, Target: gltfArrayBuffer},
+			gltfBufferView{ByteOffset: indices, ByteLength: bin.Len() - indices, Target: gltfElementArray},
+		)
+		doc.Accessors = append(doc.Accessors,
+			gltfAccessor{BufferView: 2 * i, ComponentType: gltfFloat, Count: len(mesh.vertices), Type: "VEC3", Min: lo, Max: hi},
+			gltfAccessor{BufferView: 2*i + 1, ComponentType: gltfUnsignedInt, Count: len(mesh.indices), Type: "SCALAR"},
+		)
+		doc.Materials = append(doc.Materials, gltfMaterialFor(mesh))
+		doc.Meshes = append(doc.Meshes, gltfMesh{
+			Name: mesh.name,
+			Primitives: []gltfPrimitive{{
+				Attributes: map[string]int{"POSITION": 2 * i},
+				Indices:    2*i + 1,
+				Material:   i,
+				Mode:       gltfTriangles,
+			}},
+		})
+		doc.Nodes = append(doc.Nodes, gltfNode{Name: mesh.name, Mesh: i})
+		doc.Scenes[0].Nodes = append(doc.Scenes[0].Nodes, i)
+	}
+	if bin.Len() > 0 {
+		doc.Buffers = []gltfBuffer{{ByteLength: bin.Len()}}
+	}
+
+	js, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	// Chunks are padded to 4 bytes, with spaces for JSON and zeros for
+	// binary data.
+	for len(js)%4 != 0 {
+		js = append(js, ' ')
+	}
+	for bin.Len()%4 != 0 {
+		bin.WriteByte(0)
+	}
+
+	length := 12 + 8 + len(js)
+	if bin.Len() > 0 {
+		length += 8 + bin.Len()
+	}
+
+	var out bytes.Buffer
+	binary.Write(&out, binary.LittleEndian, []uint32{glbMagic, glbVersion, uint32(length)})
+	binary.Write(&out, binary.LittleEndian, []uint32{uint32(len(js)), glbChunkJSON})
+	out.Write(js)
+	if bin.Len() > 0 {
+		binary.Write(&out, binary.LittleEndian, []uint32{uint32(bin.Len()), glbChunkBIN})
+		out.Write(bin.Bytes())
+	}
+
+	_, err = out.WriteTo(w)
+	return err
+}
+
+// gltfMaterialFor returns the matte material of the provided mesh. glTF
+// expects linear colors, so the mesh's sRGB color is converted.
+func gltfMaterialFor(mesh *modelMesh) gltfMaterial {
+	c := color.NRGBAModel.Convert(mesh.color).(color.NRGBA)
+	mat := gltfMaterial{
+		Name: mesh.name,
+		PBR: gltfPBR{
+			BaseColorFactor: [4]float64{srgbToLinear(c.R), srgbToLinear(c.G), srgbToLinear(c.B), float64(c.A) / 0xff},
+			RoughnessFactor: 1,
+		},
+	}
+	if c.A != 0xff {
+		mat.AlphaMode = "BLEND"
+	}
+
+	return mat
+}
+
+// srgbToLinear returns the linear intensity of the provided 8-bit sRGB
+// component.
+func srgbToLinear(v uint8) float64 {
+	f := float64(v) / 0xff
+	if f <= 0.04045 {
+		return f / 12.92
+	}
+
+	return math.Pow((f+0.055)/1.055, 2.4)
+}
@@ -0,0 +1,90 @@
+package mfcg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMap_WriteGLB(t *testing.T) {
+	tests := []struct {
+		name       string
+		mp         *Map
+		wantMeshes []string
+	}{
+		{
+			name:       "Map",
+			mp:         testMap(t, testFileMap),
+			wantMeshes: []string{IDEarth, IDWater, IDRivers, IDRoads, IDSquares, IDWalls, idTowers, IDPrisms},
+		},
+		{
+			name:       "Empty map",
+			mp:         &Map{},
+			wantMeshes: nil,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := test.mp.WriteGLB(&buf, nil); err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+			data := buf.Bytes()
+
+			var header [5]uint32
+			if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &header); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(header[:4], []uint32{glbMagic, glbVersion, uint32(len(data)), header[3]}); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+			if header[3]%4 != 0 || header[4] != glbChunkJSON {
+				t.Fatalf("got: <%v>, want: <%s>", header[3:], "padded JSON chunk")
+			}
+
+			var doc gltfDocument
+			if err := json.Unmarshal(data[20:20+header[3]], &doc); err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+
+			var names []string
+			for _, mesh := range doc.Meshes {
+				names = append(names, mesh.Name)
+			}
+			if diff := cmp.Diff(names, test.wantMeshes); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+
+			// The binary chunk holds every buffer view.
+			rest := data[20+header[3]:]
+			if len(doc.Buffers) == 0 {
+				if diff := cmp.Diff(len(rest), 0); diff != "" {
+					t.Errorf("mismatch (-got +want):\n%s", diff)
+				}
+				return
+			}
+			if diff := cmp.Diff(int(binary.LittleEndian.Uint32(rest)), len(rest)-8); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+			for _, bv := range doc.BufferViews {
+				if bv.ByteOffset+bv.ByteLength > doc.Buffers[0].ByteLength {
+					t.Errorf("got: <%v>, want: <%s>", bv, "view within the buffer")
+				}
+			}
+			for _, acc := range doc.Accessors {
+				size := 4
+				if acc.Type == "VEC3" {
+					size = 12
+				}
+				if diff := cmp.Diff(acc.Count*size, doc.BufferViews[acc.BufferView].ByteLength); diff != "" {
+					t.Errorf("mismatch (-got +want):\n%s", diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package mfcg
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// nodeCapacity is the maximum number of entries of a single Index node.
+const nodeCapacity = 16
+
+// FeatureRef refers to a single feature of a Map by its layer and position.
+type FeatureRef struct {
+	Layer string // Layer is the ID of the feature's layer (e.g. IDBuildings).
+	Index int    // Index is the position of the feature within its layer.
+}
+
+// Index is a spatial index over every feature of a Map. It is a static
+// R-tree bulk loaded using the Sort-Tile-Recursive algorithm. An Index does
+// not reflect changes made to its Map after it was built.
+type Index struct {
+	root *indexNode
+}
+
+// indexNode is a node of an Index. Leaf nodes hold entries, other nodes hold
+// children.
+type indexNode struct {
+	bounds   Rect
+	children []*indexNode
+	entries  []*indexEntry
+}
+
+// indexEntry is a single feature held by an Index. Polygon features are
+// held as poly, line features, including walls, as the lines drawn for them.
+type indexEntry struct {
+	ref    FeatureRef
+	bounds Rect
+	poly   *Polygon
+	lines  []LineString
+}
+
+// distance returns the distance between Point pt and the entry's feature.
+// Lines are measured to the edge of their width.
+func (e *indexEntry) distance(pt Point) float64 {
+	if e.poly != nil {
+		return e.poly.Distance(pt)
+	}
+
+	d := math.Inf(1)
+	for _, ln := range e.lines {
+		d = math.Min(d, math.Max(0, ln.Distance(pt)-ln.Width/2))
+	}
+	return d
+}
+
+// covers reports whether the entry's feature covers Point pt. Polygons
+// cover the Points they contain and lines cover the Points within half
+// their width.
+func (e *indexEntry) covers(pt Point) bool {
+	if e.poly != nil {
+		return e.poly.Contains(pt)
+	}
+
+	for _, ln := range e.lines {
+		if ln.Distance(pt) <= ln.Width/2 {
+			return true
+		}
+	}
+	return false
+}
+
+// NewIndex returns an Index over every feature of the provided Map.
+// Districts only overlay the features and are left out. Walls are indexed as
+// the lines drawn along them, WallThickness wide or, if it is zero, as wide
+// as the wall's Width.
+func NewIndex(m *Map) *Index {
+	var entries []*indexEntry
+	for _, l := range m.polygonLayers() {
+		if l.id == IDWalls {
+			continue
+		}
+		for i := range l.polys {
+			p := &l.polys[i]
+			entries = append(entries, &indexEntry{
+				ref:    FeatureRef{Layer: l.id, Index: i},
+				bounds: p.Bounds(),
+				poly:   p,
+			})
+		}
+	}
+	for i, p := range m.Walls {
+		width := m.WallThickness
+		if width == 0 {
+			width = p.Width
+		}
+
+		e := &indexEntry{
+			ref:    FeatureRef{Layer: IDWalls, Index: i},
+			bounds: p.Bounds().Inset(-width / 2),
+		}
+		for _, ring := range p.Coords {
+			e.lines = append(e.lines, LineString{Width: width, Coords: m.wallPath(ring)})
+		}
+		entries = append(entries, e)
+	}
+	for _, l := range m.lineLayers() {
+		for i, ln := range l.lines {
+			entries = append(entries, &indexEntry{
+				ref:    FeatureRef{Layer: l.id, Index: i},
+				bounds: ln.Bounds().Inset(-ln.Width / 2),
+				lines:  []LineString{ln},
+			})
+		}
+	}
+
+	return bulkLoad(entries)
+}
+
+// bulkLoad returns an Index holding the provided entries.
+func bulkLoad(entries []*indexEntry) *Index {
+	centers := make([]Point, len(entries))
+	for i, e := range entries {
+		centers[i] = e.bounds.Center()
+	}
+
+	var nodes []*indexNode
+	for _, group := range tile(centers) {
+		n := &indexNode{bounds: emptyRect()}
+		for _, i := range group {
+			n.entries = append(n.entries, entries[i])
+			n.bounds = n.bounds.Union(entries[i].bounds)
+		}
+		nodes = append(nodes, n)
+	}
+
+	for len(nodes) > nodeCapacity {
+		centers = centers[:0]
+		for _, n := range nodes {
+			centers = append(centers, n.bounds.Center())
+		}
+
+		var parents []*indexNode
+		for _, group := range tile(centers) {
+			n := &indexNode{bounds: emptyRect()}
+			for _, i := range group {
+				n.children = append(n.children, nodes[i])
+				n.bounds = n.bounds.Union(nodes[i].bounds)
+			}
+			parents = append(parents, n)
+		}
+		nodes = parents
+	}
+
+	root := &indexNode{bounds: emptyRect(), children: nodes}
+	for _, c := range nodes {
+		root.bounds = root.bounds.Union(c.bounds)
+	}
+
+	return &Index{root: root}
+}
+
+// tile groups items into nodes given the centers of the items. Items are
+// sorted into vertical slices by the X coordinate of their centers, then
+// each slice is sorted by the Y coordinate of their centers and split into
+// nodes. Each group lists the indices of its items.
+func tile(centers []Point) [][]int {
+	order := make([]int, len(centers))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return centers[order[i]].X < centers[order[j]].X })
+
+	leaves := (len(order) + nodeCapacity - 1) / nodeCapacity
+	sliceSize := int(math.Ceil(math.Sqrt(float64(leaves)))) * nodeCapacity
+
+	var groups [][]int
+	for start := 0; start < len(order); start += sliceSize {
+		slice := order[start:minInt(start+sliceSize, len(order))]
+		sort.Slice(slice, func(i, j int) bool { return centers[slice[i]].Y < centers[slice[j]].Y })
+
+		for lo := 0; lo < len(slice); lo += nodeCapacity {
+			groups = append(groups, slice[lo:minInt(lo+nodeCapacity, len(slice))])
+		}
+	}
+
+	return groups
+}
+
+// minInt returns the smaller of a and b.
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Query returns the features whose bounds intersect the provided Rect.
+func (ix *Index) Query(r Rect) []FeatureRef {
+	var refs []FeatureRef
+	ix.search(func(b Rect) bool { return b.Intersects(r) }, func(e *indexEntry) {
+		refs = append(refs, e.ref)
+	})
+
+	return refs
+}
+
+// At returns the features covering Point pt. Polygons cover the Points
+// within them but outside their holes. LineStrings and walls cover the
+// Points within half their width of their center line.
+func (ix *Index) At(pt Point) []FeatureRef {
+	var refs []FeatureRef
+	ix.search(func(b Rect) bool { return b.Contains(pt) }, func(e *indexEntry) {
+		if e.covers(pt) {
+			refs = append(refs, e.ref)
+		}
+	})
+
+	return refs
+}
+
+// search calls fn for every entry whose bounds, and whose ancestors' bounds,
+// satisfy match.
+func (ix *Index) search(match func(Rect) bool, fn func(*indexEntry)) {
+	stack := []*indexNode{ix.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !match(n.bounds) {
+			continue
+		}
+
+		for _, e := range n.entries {
+			if match(e.bounds) {
+				fn(e)
+			}
+		}
+		stack = append(stack, n.children...)
+	}
+}
+
+// Nearest returns the k features closest to Point pt, ordered from closest
+// to farthest. Features covering pt are at distance zero and LineStrings
+// and walls are measured to the edge of their width.
+func (ix *Index) Nearest(pt Point, k int) []FeatureRef {
+	var refs []FeatureRef
+	q := &searchQueue{{node: ix.root, dist: ix.root.bounds.Distance(pt)}}
+	for q.Len() > 0 && len(refs) < k {
+		item := heap.Pop(q).(searchItem)
+		switch {
+		case item.node != nil:
+			for _, c := range item.node.children {
+				heap.Push(q, searchItem{node: c, dist: c.bounds.Distance(pt)})
+			}
+			for _, e := range item.node.entries {
+				heap.Push(q, searchItem{entry: e, dist: e.bounds.Distance(pt)})
+			}
+		case !item.exact:
+			heap.Push(q, searchItem{entry: item.entry, dist: item.entry.distance(pt), exact: true})
+		default:
+			refs = append(refs, item.entry.ref)
+		}
+	}
+
+	return refs
+}
+
+// searchItem is a node or entry awaiting a visit during a nearest neighbor
+// search. The distance of an entry is exact once its feature has been
+// measured and a lower bound given by its bounds otherwise.
+type searchItem struct {
+	node  *indexNode
+	entry *indexEntry
+	dist  float64
+	exact bool
+}
+
+// searchQueue is a min-heap of searchItems implementing heap.Interface.
+type searchQueue []searchItem
+
+func (q searchQueue) Len() int            { return len(q) }
+func (q searchQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q searchQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *searchQueue) Push(x interface{}) { *q = append(*q, x.(searchItem)) }
+func (q *searchQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,233 @@
+package mfcg
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// testGridMap returns a Map of n by n unit square buildings spaced two units
+// apart, a road along the X axis and an earth polygon with a hole.
+func testGridMap(n int) *Map {
+	mp := &Map{
+		Earth: Polygon{Coords: [][]Point{
+			{{X: -10, Y: -10}, {X: 100, Y: -10}, {X: 100, Y: 100}, {X: -10, Y: 100}},
+			{{X: -9, Y: -9}, {X: -5, Y: -9}, {X: -5, Y: -5}, {X: -9, Y: -5}},
+		}},
+		Roads: []LineString{{Width: 2, Coords: []Point{{X: -10, Y: -2}, {X: 100, Y: -2}}}},
+	}
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			fx, fy := float64(2*x), float64(2*y)
+			mp.Buildings = append(mp.Buildings, Polygon{Coords: [][]Point{
+				{{X: fx, Y: fy}, {X: fx + 1, Y: fy}, {X: fx + 1, Y: fy + 1}, {X: fx, Y: fy + 1}},
+			}})
+		}
+	}
+
+	return mp
+}
+
+// sortRefs orders FeatureRefs by layer then index.
+var sortRefs = cmpopts.SortSlices(func(a, b FeatureRef) bool {
+	if a.Layer != b.Layer {
+		return a.Layer < b.Layer
+	}
+	return a.Index < b.Index
+})
+
+func TestIndex_Query(t *testing.T) {
+	mp := testGridMap(20)
+	ix := NewIndex(mp)
+
+	tests := []struct {
+		name string
+		rect Rect
+		want []FeatureRef
+	}{
+		{
+			name: "Single building",
+			rect: Rect{Min: Point{X: 4.2, Y: 6.2}, Max: Point{X: 4.8, Y: 6.8}},
+			want: []FeatureRef{{Layer: IDEarth, Index: 0}, {Layer: IDBuildings, Index: 2*20 + 3}},
+		},
+		{
+			name: "Between buildings",
+			rect: Rect{Min: Point{X: 5.2, Y: 5.2}, Max: Point{X: 5.8, Y: 5.8}},
+			want: []FeatureRef{{Layer: IDEarth, Index: 0}},
+		},
+		{
+			name: "Road and buildings",
+			rect: Rect{Min: Point{X: 0.5, Y: -1.5}, Max: Point{X: 2.5, Y: 0.5}},
+			want: []FeatureRef{
+				{Layer: IDEarth, Index: 0},
+				{Layer: IDRoads, Index: 0},
+				{Layer: IDBuildings, Index: 0},
+				{Layer: IDBuildings, Index: 20},
+			},
+		},
+		{
+			name: "Outside map",
+			rect: Rect{Min: Point{X: 200, Y: 200}, Max: Point{X: 300, Y: 300}},
+			want: nil,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := ix.Query(test.rect)
+			if diff := cmp.Diff(got, test.want, sortRefs, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIndex_At(t *testing.T) {
+	ix := NewIndex(testGridMap(20))
+
+	tests := []struct {
+		name string
+		pt   Point
+		want []FeatureRef
+	}{
+		{
+			name: "Building",
+			pt:   Point{X: 10.5, Y: 12.5},
+			want: []FeatureRef{{Layer: IDEarth, Index: 0}, {Layer: IDBuildings, Index: 5*20 + 6}},
+		},
+		{
+			name: "Road",
+			pt:   Point{X: 30, Y: -1.2},
+			want: []FeatureRef{{Layer: IDEarth, Index: 0}, {Layer: IDRoads, Index: 0}},
+		},
+		{
+			name: "Earth hole",
+			pt:   Point{X: -7, Y: -7},
+			want: nil,
+		},
+		{
+			name: "Outside map",
+			pt:   Point{X: -20, Y: 0},
+			want: nil,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := ix.At(test.pt)
+			if diff := cmp.Diff(got, test.want, sortRefs, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIndex_Nearest(t *testing.T) {
+	mp := testGridMap(20)
+	mp.Earth = Polygon{}
+	mp.Roads = nil
+	ix := NewIndex(mp)
+
+	pts := []Point{{X: 13.7, Y: 21.2}, {X: -30, Y: 12}, {X: 55, Y: 55}, {X: 0.5, Y: 0.5}}
+	for _, pt := range pts {
+		want := make([]int, len(mp.Buildings))
+		for i := range want {
+			want[i] = i
+		}
+		sort.SliceStable(want, func(i, j int) bool {
+			return mp.Buildings[want[i]].Distance(pt) < mp.Buildings[want[j]].Distance(pt)
+		})
+
+		got := ix.Nearest(pt, 5)
+		if len(got) != 5 {
+			t.Fatalf("got: <%v> features, want: <%v>", len(got), 5)
+		}
+		for i, ref := range got {
+			gotDist := mp.Buildings[ref.Index].Distance(pt)
+			wantDist := mp.Buildings[want[i]].Distance(pt)
+			if diff := cmp.Diff(gotDist, wantDist); diff != "" {
+				t.Errorf("%v: mismatch (-got +want):\n%s", pt, diff)
+			}
+		}
+	}
+
+	if got := NewIndex(&Map{}).Nearest(Point{}, 3); len(got) != 0 {
+		t.Errorf("got: <%v>, want no features", got)
+	}
+}
+
+func TestIndex_walls(t *testing.T) {
+	mp := &Map{
+		MetaData: MetaData{WallThickness: 4},
+		Walls: []Polygon{{Coords: [][]Point{
+			{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 100}, {X: 0, Y: 100}},
+		}}},
+		Roads: []LineString{{Width: 10, Coords: []Point{{X: 20, Y: 50}, {X: 80, Y: 50}}}},
+	}
+	ix := NewIndex(mp)
+
+	tests := []struct {
+		name string
+		pt   Point
+		want []FeatureRef
+	}{
+		{
+			name: "Middle of the city",
+			pt:   Point{X: 50, Y: 70},
+			want: nil,
+		},
+		{
+			name: "On the wall",
+			pt:   Point{X: 50, Y: 1.5},
+			want: []FeatureRef{{Layer: IDWalls, Index: 0}},
+		},
+		{
+			name: "On the closing segment",
+			pt:   Point{X: -1.5, Y: 50},
+			want: []FeatureRef{{Layer: IDWalls, Index: 0}},
+		},
+		{
+			name: "Beside the wall",
+			pt:   Point{X: 50, Y: 2.5},
+			want: nil,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := ix.At(test.pt)
+			if diff := cmp.Diff(got, test.want, sortRefs, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+
+	got := ix.Nearest(Point{X: 50, Y: 24}, 2)
+	want := []FeatureRef{{Layer: IDRoads, Index: 0}, {Layer: IDWalls, Index: 0}}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	mp.OpenWalls = true
+	if got := NewIndex(mp).At(Point{X: -1.5, Y: 50}); len(got) != 0 {
+		t.Errorf("got: <%v>, want no features", got)
+	}
+}
+
+func TestNewIndex_testData(t *testing.T) {
+	mp := testMap(t, testFileMap)
+	ix := NewIndex(mp)
+
+	got := ix.At(mp.Squares[0].Centroid())
+	want := []FeatureRef{{Layer: IDSquares, Index: 0}}
+	if diff := cmp.Diff(got, want, sortRefs); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	all := ix.Query(mp.Bounds())
+	if diff := cmp.Diff(len(all), 13); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
@@ -1,6 +1,9 @@
 package mfcg
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"math"
+)
 
 // LineString is a path between a set of Points.
 type LineString struct {
@@ -12,20 +15,124 @@ type LineString struct {
 // provided GeometryCollection data. The data must conform to a slice of MFCG's
 // proprietary linestring geometries.
 func geosToLineStrings(data []byte) ([]LineString, error) {
-	var geos []*json.RawMessage
+	var geos []json.RawMessage
 	if err := json.Unmarshal(data, &geos); err != nil {
 		return nil, err
 	}
 
 	var lines []LineString
-	for _, g := range geos {
-		var ln LineString
-		if err := json.Unmarshal(*g, &ln); err != nil {
-			return nil, err
+	for i, g := range geos {
+		ln, err := geoToLineString(g)
+		if err != nil {
+			pe := locate(err)
+			pe.Geometry = i
+			return nil, pe
 		}
-		
-		lines = append(lines, ln)
+
+		lines = append(lines, *ln)
 	}
 
 	return lines, nil
 }
+
+// geoToLineString returns a LineString corresponding to the provided
+// geometry data. The data must conform to one of MFCG's proprietary
+// linestring geometries.
+func geoToLineString(data []byte) (*LineString, error) {
+	var geo struct {
+		Width       float64         `json:"width"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+	if err := json.Unmarshal(data, &geo); err != nil {
+		return nil, err
+	}
+
+	if geo.Coordinates == nil {
+		return &LineString{Width: geo.Width}, nil
+	}
+
+	pts, err := decodePoints(geo.Coordinates)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LineString{Width: geo.Width, Coords: pts}, nil
+}
+
+// lineStringsToGeos returns the GeometryCollection data corresponding to the
+// provided LineStrings. The data conforms to a slice of MFCG's proprietary
+// linestring geometries.
+func lineStringsToGeos(lines []LineString) ([]byte, error) {
+	geos := make([]geometry, len(lines))
+	for i, ln := range lines {
+		geos[i] = geometry{
+			Type:        typeLineString,
+			Width:       ln.Width,
+			Coordinates: ln.Coords,
+		}
+	}
+
+	return json.Marshal(geos)
+}
+
+// Length returns the length of the LineString.
+func (ln LineString) Length() float64 {
+	return pathLength(ln.Coords)
+}
+
+// Interpolate returns the Point at fraction t of the LineString's length,
+// where 0 is its first Point and 1 is its last. The fraction is clamped to
+// that range.
+func (ln LineString) Interpolate(t float64) Point {
+	if len(ln.Coords) == 0 {
+		return Point{}
+	}
+
+	t = math.Max(0, math.Min(1, t))
+	remaining := t * ln.Length()
+	for i := 1; i < len(ln.Coords); i++ {
+		a, b := ln.Coords[i-1], ln.Coords[i]
+		d := distance(a, b)
+		if d > 0 && remaining <= d {
+			f := remaining / d
+			return Point{X: a.X + (b.X-a.X)*f, Y: a.Y + (b.Y-a.Y)*f}
+		}
+		remaining -= d
+	}
+
+	return ln.Coords[len(ln.Coords)-1]
+}
+
+// Bounds returns the smallest Rect containing every Point of the LineString.
+func (ln LineString) Bounds() Rect {
+	return emptyRect().extend(ln.Coords)
+}
+
+// pathLength returns the total length of the segments between consecutive
+// Points of the provided path.
+func pathLength(pts []Point) float64 {
+	var length float64
+	for i := 1; i < len(pts); i++ {
+		length += distance(pts[i-1], pts[i])
+	}
+
+	return length
+}
+
+// Distance returns the euclidean distance between Point pt and the
+// LineString's center line.
+func (ln LineString) Distance(pt Point) float64 {
+	switch len(ln.Coords) {
+	case 0:
+		return math.Inf(1)
+	case 1:
+		return distance(pt, ln.Coords[0])
+	}
+
+	d := math.Inf(1)
+	for i := 1; i < len(ln.Coords); i++ {
+		d = math.Min(d, segmentDistance(pt, ln.Coords[i-1], ln.Coords[i]))
+	}
+
+	return d
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func Test_geosToLineStrings(t *testing.T) {
@@ -77,3 +78,135 @@ func Test_geosToLineStrings(t *testing.T) {
 		})
 	}
 }
+
+func TestLineString_Length(t *testing.T) {
+	tests := []struct {
+		name string
+		line LineString
+		want float64
+	}{
+		{
+			name: "Multiple segments",
+			line: LineString{Coords: []Point{{X: 0, Y: 0}, {X: 3, Y: 4}, {X: 3, Y: -1}}},
+			want: 10,
+		},
+		{
+			name: "Single point",
+			line: LineString{Coords: []Point{{X: 3, Y: 4}}},
+			want: 0,
+		},
+		{
+			name: "Test data river",
+			line: testMap(t, testFileMap).Rivers[0],
+			want: 79.13700471616188,
+		},
+		{
+			name: "Test data road",
+			line: testMap(t, testFileMap).Roads[0],
+			want: 139.8893591391887,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := test.line.Length()
+			if diff := cmp.Diff(got, test.want, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLineString_Interpolate(t *testing.T) {
+	line := LineString{Coords: []Point{{X: 0, Y: 0}, {X: 3, Y: 4}, {X: 3, Y: 4}, {X: 3, Y: -1}}}
+	tests := []struct {
+		name string
+		line LineString
+		t    float64
+		want Point
+	}{
+		{name: "Start", line: line, t: 0, want: Point{X: 0, Y: 0}},
+		{name: "First segment", line: line, t: 0.25, want: Point{X: 1.5, Y: 2}},
+		{name: "Shared vertex", line: line, t: 0.5, want: Point{X: 3, Y: 4}},
+		{name: "Last segment", line: line, t: 0.8, want: Point{X: 3, Y: 1}},
+		{name: "End", line: line, t: 1, want: Point{X: 3, Y: -1}},
+		{name: "Clamped below", line: line, t: -2, want: Point{X: 0, Y: 0}},
+		{name: "Clamped above", line: line, t: 3, want: Point{X: 3, Y: -1}},
+		{name: "Single point", line: LineString{Coords: []Point{{X: 7, Y: 8}}}, t: 0.5, want: Point{X: 7, Y: 8}},
+		{name: "No points", line: LineString{}, t: 0.5, want: Point{}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := test.line.Interpolate(test.t)
+			if diff := cmp.Diff(got, test.want, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLineString_Bounds(t *testing.T) {
+	tests := []struct {
+		name string
+		line LineString
+		want Rect
+	}{
+		{
+			name: "Test data river",
+			line: testMap(t, testFileMap).Rivers[0],
+			want: Rect{Min: Point{X: 59.021, Y: -128.437}, Max: Point{X: 93.579, Y: -60.776}},
+		},
+		{
+			name: "No points",
+			line: LineString{},
+			want: emptyRect(),
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := test.line.Bounds()
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLineString_Distance(t *testing.T) {
+	tests := []struct {
+		name string
+		line LineString
+		pt   Point
+		want float64
+	}{
+		{
+			name: "Beside segment",
+			line: LineString{Coords: []Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}},
+			pt:   Point{X: 5, Y: -2},
+			want: 2,
+		},
+		{
+			name: "Past endpoint",
+			line: LineString{Coords: []Point{{X: 0, Y: 0}, {X: 10, Y: 0}}},
+			pt:   Point{X: 13, Y: 4},
+			want: 5,
+		},
+		{
+			name: "Single point",
+			line: LineString{Coords: []Point{{X: 1, Y: 1}}},
+			pt:   Point{X: 4, Y: 5},
+			want: 5,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := test.line.Distance(test.pt)
+			if diff := cmp.Diff(got, test.want, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
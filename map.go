@@ -13,11 +13,20 @@ const (
 	IDSquares   string = "squares"
 	IDWalls     string = "walls"
 	IDWater     string = "water"
+	IDTrees     string = "trees"
+	IDDistricts string = "districts"
 	IDValues    string = "values"
 )
 
 // Map represents a cartographical map. It contains a collection of features
-// commonly used to represent a medieval fantasy city.
+// commonly used to represent a medieval fantasy city. Warnings lists the
+// problems tolerated while decoding the Map, if any.
+//
+// MFCG's walls are closed: each ring of Walls is drawn back to its first
+// Point. OpenWalls reports that each ring is instead a path drawn as is, as
+// left by Clip once it cuts walls at the edge of its region. A wall left
+// whole then repeats its first Point at its end. OpenWalls is not encoded,
+// since MFCG's data has no open walls.
 type Map struct {
 	MetaData
 	Earth     Polygon      `json:"earth,omitempty"`
@@ -31,6 +40,11 @@ type Map struct {
 	Squares   []Polygon    `json:"squares,omitempty"`
 	Walls     []Polygon    `json:"walls,omitempty"`
 	Water     []Polygon    `json:"water,omitempty"`
+	Trees     []Point      `json:"trees,omitempty"`
+	Districts []District   `json:"districts,omitempty"`
+	Extra     []Feature    `json:"extra,omitempty"`
+	OpenWalls bool         `json:"-"`
+	Warnings  []error      `json:"-"`
 }
 
 // MetaData contains various map feature parameters and generator details.
@@ -43,107 +57,262 @@ type MetaData struct {
 	Version       string  `json:"version,omitempty"`
 }
 
+// layer describes how one of MFCG's features is decoded into a Map.
+type layer struct {
+	id     string                         // ID of the feature
+	typ    string                         // geometry type MFCG exports the feature as
+	decode func(ft feature, m *Map) error // decodes the feature into m
+}
+
+// layers lists every layer known to this package in the order MFCG exports
+// them.
+var layers = []layer{
+	{id: IDValues, typ: typeFeature, decode: func(ft feature, m *Map) error {
+		m.MetaData = ft.MetaData
+		return nil
+	}},
+	{id: IDEarth, typ: typePolygon, decode: func(ft feature, m *Map) error {
+		p, err := coordsToPolygon(ft.Coordinates)
+		if err != nil {
+			return err
+		}
+		m.Earth = *p
+		return nil
+	}},
+	{id: IDPlanks, typ: typeGeometryCollection, decode: func(ft feature, m *Map) (err error) {
+		m.Planks, err = geosToLineStrings(ft.Geometries)
+		return err
+	}},
+	{id: IDRivers, typ: typeGeometryCollection, decode: func(ft feature, m *Map) (err error) {
+		m.Rivers, err = geosToLineStrings(ft.Geometries)
+		return err
+	}},
+	{id: IDRoads, typ: typeGeometryCollection, decode: func(ft feature, m *Map) (err error) {
+		m.Roads, err = geosToLineStrings(ft.Geometries)
+		return err
+	}},
+	{id: IDBuildings, typ: typeMultiPolygon, decode: func(ft feature, m *Map) (err error) {
+		m.Buildings, err = coordsToPolygons(ft.Coordinates)
+		return err
+	}},
+	{id: IDFields, typ: typeMultiPolygon, decode: func(ft feature, m *Map) (err error) {
+		m.Fields, err = coordsToPolygons(ft.Coordinates)
+		return err
+	}},
+	{id: IDGreens, typ: typeMultiPolygon, decode: func(ft feature, m *Map) (err error) {
+		m.Greens, err = coordsToPolygons(ft.Coordinates)
+		return err
+	}},
+	{id: IDPrisms, typ: typeMultiPolygon, decode: func(ft feature, m *Map) (err error) {
+		m.Prisms, err = coordsToPolygons(ft.Coordinates)
+		return err
+	}},
+	{id: IDSquares, typ: typeMultiPolygon, decode: func(ft feature, m *Map) (err error) {
+		m.Squares, err = coordsToPolygons(ft.Coordinates)
+		return err
+	}},
+	{id: IDWalls, typ: typeGeometryCollection, decode: func(ft feature, m *Map) (err error) {
+		m.Walls, err = geosToPolygons(ft.Geometries)
+		return err
+	}},
+	{id: IDWater, typ: typeMultiPolygon, decode: func(ft feature, m *Map) (err error) {
+		m.Water, err = coordsToPolygons(ft.Coordinates)
+		return err
+	}},
+	{id: IDTrees, typ: typeMultiPoint, decode: func(ft feature, m *Map) (err error) {
+		m.Trees, err = coordsToPoints(ft.Coordinates)
+		return err
+	}},
+	{id: IDDistricts, typ: typeGeometryCollection, decode: func(ft feature, m *Map) (err error) {
+		m.Districts, err = geosToDistricts(ft.Geometries)
+		return err
+	}},
+}
+
 // toMap transforms the provided feature map into a cartographical Map. The
-// feature map's keys must match each features' respective ID.
-func toMap(feats map[string]feature) (*Map, error) {
+// feature map's keys must match each features' respective ID. If cfg is
+// lenient, features which fail to decode are left out of the Map and reported
+// in its Warnings.
+func toMap(feats map[string]feature, cfg config) (*Map, error) {
 	var result Map
 
-	if ft, ok := feats[IDEarth]; ok {
-		p, err := coordsToPolygon(ft.Coordinates)
+	for _, l := range layers {
+		ft, ok := feats[l.id]
+		if !ok {
+			continue
+		}
+
+		if err := l.decode(ft, &result); err != nil {
+			if !cfg.lenient {
+				return nil, ft.locate(err)
+			}
+			result.Warnings = append(result.Warnings, ft.locate(err))
+		}
+	}
+
+	return &result, nil
+}
+
+// findLayer returns the layer with the provided ID, if any.
+func findLayer(id string) (layer, bool) {
+	for _, l := range layers {
+		if l.id == id {
+			return l, true
+		}
+	}
+	return layer{}, false
+}
+
+// fromMap transforms the provided cartographical Map into a slice of features
+// ordered as MFCG exports them. Layers with a nil value are omitted.
+func fromMap(m *Map) ([]*feature, error) {
+	feats := []*feature{
+		{Type: typeFeature, ID: IDValues, MetaData: m.MetaData},
+	}
+
+	if m.Earth.Coords != nil {
+		data, err := polygonToCoords(m.Earth)
 		if err != nil {
 			return nil, err
 		}
-		result.Earth = *p
+		feats = append(feats, &feature{Type: typePolygon, ID: IDEarth, Coordinates: data})
 	}
 
-	if ft, ok := feats[IDPlanks]; ok {
-		ls, err := geosToLineStrings(ft.Geometries)
+	if m.Planks != nil {
+		data, err := lineStringsToGeos(m.Planks)
 		if err != nil {
 			return nil, err
 		}
-		result.Planks = ls
+		feats = append(feats, &feature{Type: typeGeometryCollection, ID: IDPlanks, Geometries: data})
 	}
 
-	if ft, ok := feats[IDRivers]; ok {
-		ls, err := geosToLineStrings(ft.Geometries)
+	if m.Rivers != nil {
+		data, err := lineStringsToGeos(m.Rivers)
 		if err != nil {
 			return nil, err
 		}
-		result.Rivers = ls
+		feats = append(feats, &feature{Type: typeGeometryCollection, ID: IDRivers, Geometries: data})
 	}
 
-	if ft, ok := feats[IDRoads]; ok {
-		ls, err := geosToLineStrings(ft.Geometries)
+	if m.Roads != nil {
+		data, err := lineStringsToGeos(m.Roads)
 		if err != nil {
 			return nil, err
 		}
-		result.Roads = ls
+		feats = append(feats, &feature{Type: typeGeometryCollection, ID: IDRoads, Geometries: data})
 	}
 
-	if ft, ok := feats[IDBuildings]; ok {
-		p, err := coordsToPolygons(ft.Coordinates)
+	if m.Buildings != nil {
+		data, err := polygonsToCoords(m.Buildings)
 		if err != nil {
 			return nil, err
 		}
-		result.Buildings = p
+		feats = append(feats, &feature{Type: typeMultiPolygon, ID: IDBuildings, Coordinates: data})
 	}
 
-	if ft, ok := feats[IDFields]; ok {
-		p, err := coordsToPolygons(ft.Coordinates)
+	if m.Fields != nil {
+		data, err := polygonsToCoords(m.Fields)
 		if err != nil {
 			return nil, err
 		}
-		result.Fields = p
+		feats = append(feats, &feature{Type: typeMultiPolygon, ID: IDFields, Coordinates: data})
 	}
 
-	if ft, ok := feats[IDGreens]; ok {
-		p, err := coordsToPolygons(ft.Coordinates)
+	if m.Greens != nil {
+		data, err := polygonsToCoords(m.Greens)
 		if err != nil {
 			return nil, err
 		}
-		result.Greens = p
+		feats = append(feats, &feature{Type: typeMultiPolygon, ID: IDGreens, Coordinates: data})
 	}
 
-	if ft, ok := feats[IDPrisms]; ok {
-		p, err := coordsToPolygons(ft.Coordinates)
+	if m.Prisms != nil {
+		data, err := polygonsToCoords(m.Prisms)
 		if err != nil {
 			return nil, err
 		}
-		result.Prisms = p
+		feats = append(feats, &feature{Type: typeMultiPolygon, ID: IDPrisms, Coordinates: data})
 	}
 
-	if ft, ok := feats[IDSquares]; ok {
-		p, err := coordsToPolygons(ft.Coordinates)
+	if m.Squares != nil {
+		data, err := polygonsToCoords(m.Squares)
 		if err != nil {
 			return nil, err
 		}
-		result.Squares = p
+		feats = append(feats, &feature{Type: typeMultiPolygon, ID: IDSquares, Coordinates: data})
 	}
 
-	if ft, ok := feats[IDWalls]; ok {
-		p, err := geosToPolygons(ft.Geometries)
+	if m.Walls != nil {
+		data, err := polygonsToGeos(m.Walls)
 		if err != nil {
 			return nil, err
 		}
-		result.Walls = p
+		feats = append(feats, &feature{Type: typeGeometryCollection, ID: IDWalls, Geometries: data})
 	}
 
-	if ft, ok := feats[IDWater]; ok {
-		p, err := coordsToPolygons(ft.Coordinates)
+	if m.Water != nil {
+		data, err := polygonsToCoords(m.Water)
 		if err != nil {
 			return nil, err
 		}
-		result.Water = p
+		feats = append(feats, &feature{Type: typeMultiPolygon, ID: IDWater, Coordinates: data})
 	}
 
-	if ft, ok := feats[IDValues]; ok {
-		result.RoadWidth = ft.RoadWidth
-		result.RiverWidth = ft.RiverWidth
-		result.WallThickness = ft.WallThickness
-		result.TowerRadius = ft.TowerRadius
-		result.Generator = ft.Generator
-		result.Version = ft.Version
+	if m.Trees != nil {
+		data, err := pointsToCoords(m.Trees)
+		if err != nil {
+			return nil, err
+		}
+		feats = append(feats, &feature{Type: typeMultiPoint, ID: IDTrees, Coordinates: data})
 	}
 
-	return &result, nil
+	if m.Districts != nil {
+		data, err := districtsToGeos(m.Districts)
+		if err != nil {
+			return nil, err
+		}
+		feats = append(feats, &feature{Type: typeGeometryCollection, ID: IDDistricts, Geometries: data})
+	}
+
+	return feats, nil
+}
+
+// polygonLayer is a layer of a Map made of Polygons.
+type polygonLayer struct {
+	id    string
+	polys []Polygon
+}
+
+// lineLayer is a layer of a Map made of LineStrings.
+type lineLayer struct {
+	id    string
+	lines []LineString
+}
+
+// polygonLayers returns each layer of the Map made of Polygons. The earth is
+// returned as a layer holding a single Polygon if the Map has one.
+func (m *Map) polygonLayers() []polygonLayer {
+	var layers []polygonLayer
+	if m.Earth.Coords != nil {
+		layers = append(layers, polygonLayer{id: IDEarth, polys: []Polygon{m.Earth}})
+	}
+
+	return append(layers,
+		polygonLayer{id: IDBuildings, polys: m.Buildings},
+		polygonLayer{id: IDFields, polys: m.Fields},
+		polygonLayer{id: IDGreens, polys: m.Greens},
+		polygonLayer{id: IDPrisms, polys: m.Prisms},
+		polygonLayer{id: IDSquares, polys: m.Squares},
+		polygonLayer{id: IDWalls, polys: m.Walls},
+		polygonLayer{id: IDWater, polys: m.Water},
+	)
+}
+
+// lineLayers returns each layer of the Map made of LineStrings.
+func (m *Map) lineLayers() []lineLayer {
+	return []lineLayer{
+		{id: IDPlanks, lines: m.Planks},
+		{id: IDRivers, lines: m.Rivers},
+		{id: IDRoads, lines: m.Roads},
+	}
 }
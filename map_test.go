@@ -19,6 +19,8 @@ func Test_toMap(t *testing.T) {
 		IDSquares:   {Coordinates: []byte(`[[[[99.9, 99.9]]]]`)},
 		IDWalls:     {Geometries: []byte(`[{"coordinates": [[[10.10, 10.10]]]}]`)},
 		IDWater:     {Coordinates: []byte(`[[[[11.11, 11.11]]]]`)},
+		IDTrees:     {Coordinates: []byte(`[[12.12, 12.12]]`)},
+		IDDistricts: {Geometries: []byte(`[{"name": "foo", "coordinates": [[[13.13, 13.13]]]}]`)},
 		IDValues: {MetaData: MetaData{
 			RoadWidth:     12,
 			RiverWidth:    13.13,
@@ -40,6 +42,8 @@ func Test_toMap(t *testing.T) {
 		Squares:   []Polygon{{Coords: [][]Point{{{X: 99.9, Y: 99.9}}}}},
 		Walls:     []Polygon{{Coords: [][]Point{{{X: 10.1, Y: 10.1}}}}},
 		Water:     []Polygon{{Coords: [][]Point{{{X: 11.11, Y: 11.11}}}}},
+		Trees:     []Point{{X: 12.12, Y: 12.12}},
+		Districts: []District{{Name: "foo", Polygon: Polygon{Coords: [][]Point{{{X: 13.13, Y: 13.13}}}}}},
 	}
 	tableMapWithValues := tableMapNoValues
 	tableMapWithValues.MetaData = MetaData{
@@ -141,6 +145,20 @@ func Test_toMap(t *testing.T) {
 			want:         nil,
 			wantErr:      true,
 		},
+		{
+			name:         "Invalid Trees",
+			replaceKey:   IDTrees,
+			replaceValue: feature{Coordinates: []byte(`["foobar"]`)},
+			want:         nil,
+			wantErr:      true,
+		},
+		{
+			name:         "Invalid Districts",
+			replaceKey:   IDDistricts,
+			replaceValue: feature{Coordinates: []byte(`["foobar"]`)},
+			want:         nil,
+			wantErr:      true,
+		},
 		{
 			name:         "Missing Values",
 			replaceKey:   IDValues,
@@ -158,7 +176,7 @@ func Test_toMap(t *testing.T) {
 			}
 			feats[test.replaceKey] = test.replaceValue
 
-			got, err := toMap(feats)
+			got, err := toMap(feats, config{})
 			if (err != nil) != test.wantErr {
 				t.Errorf("got: <%v>, want error: <%v>", err, test.wantErr)
 				return
@@ -170,3 +188,77 @@ func Test_toMap(t *testing.T) {
 		})
 	}
 }
+
+func Test_fromMap(t *testing.T) {
+	full := Map{
+		Earth:     Polygon{Coords: [][]Point{{{X: 11.1, Y: 11.1}}}},
+		Planks:    []LineString{{Width: 2, Coords: []Point{{X: 22.2, Y: 22.2}}}},
+		Rivers:    []LineString{{Width: 3, Coords: []Point{{X: 33.3, Y: 33.3}}}},
+		Roads:     []LineString{{Width: 4, Coords: []Point{{X: 44.4, Y: 44.4}}}},
+		Buildings: []Polygon{{Coords: [][]Point{{{X: 55.5, Y: 55.5}}}}},
+		Fields:    []Polygon{{Coords: [][]Point{{{X: 66.6, Y: 66.6}}}}},
+		Greens:    []Polygon{},
+		Prisms:    []Polygon{{Coords: [][]Point{{{X: 88.8, Y: 88.8}}}}},
+		Squares:   []Polygon{{Coords: [][]Point{{{X: 99.9, Y: 99.9}}}}},
+		Walls:     []Polygon{{Width: 5, Coords: [][]Point{{{X: 10.1, Y: 10.1}}}}},
+		Water:     []Polygon{{Coords: [][]Point{{{X: 11.11, Y: 11.11}}}}},
+		Trees:     []Point{{X: 12.12, Y: 12.12}},
+		Districts: []District{{Name: "bar", Polygon: Polygon{Width: 6, Coords: [][]Point{{{X: 13.13, Y: 13.13}}}}}},
+		MetaData: MetaData{
+			RoadWidth:     12,
+			RiverWidth:    13.13,
+			TowerRadius:   14.14,
+			WallThickness: 15.15,
+			Generator:     "foo",
+			Version:       "bar",
+		},
+	}
+	tests := []struct {
+		name    string
+		mp      *Map
+		wantIDs []string
+	}{
+		{
+			name: "Full map",
+			mp:   &full,
+			wantIDs: []string{
+				IDValues, IDEarth, IDPlanks, IDRivers, IDRoads, IDBuildings, IDFields,
+				IDGreens, IDPrisms, IDSquares, IDWalls, IDWater, IDTrees, IDDistricts,
+			},
+		},
+		{
+			name:    "Empty map",
+			mp:      &Map{},
+			wantIDs: []string{IDValues},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			feats, err := fromMap(test.mp)
+			if err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+
+			var ids []string
+			byID := make(map[string]feature)
+			for _, ft := range feats {
+				ids = append(ids, ft.ID)
+				byID[ft.ID] = *ft
+			}
+
+			if diff := cmp.Diff(ids, test.wantIDs); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+
+			got, err := toMap(byID, config{})
+			if err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+
+			if diff := cmp.Diff(got, test.mp); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
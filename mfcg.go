@@ -2,28 +2,141 @@ package mfcg
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 )
 
-// New reads the provided MFCG data from r and returns the corresponding Map.
+// New reads the provided MFCG data from r and returns the corresponding Map,
+// decoding it as NewWithOptions does without options. If the data cannot be
+// decoded, the returned error is a *ParseError locating the failure.
 func New(r io.Reader) (*Map, error) {
-	var collect featureCollection
+	return decode(r, config{})
+}
+
+// decode reads the provided MFCG data from r according to cfg and returns the
+// corresponding Map.
+func decode(r io.Reader, cfg config) (*Map, error) {
+	var collect rawFeatureCollection
 	if err := json.NewDecoder(r).Decode(&collect); err != nil {
-		return nil, err
+		return nil, locate(err)
+	}
+
+	var warns []error
+	var fts []feature
+	var md MetaData
+	hasValues := false
+	for i, raw := range collect.Features {
+		ft := feature{index: i}
+		if err := json.Unmarshal(raw, &ft); err != nil {
+			pe := locate(err)
+			pe.Feature = i
+			if !cfg.lenient {
+				return nil, pe
+			}
+			warns = append(warns, pe)
+			continue
+		}
+		if ft.ID == IDValues && !hasValues {
+			md = ft.MetaData
+			hasValues = true
+		}
+		fts = append(fts, ft)
+	}
+
+	if cfg.strict && !hasValues {
+		return nil, locate(ErrMissingValues)
+	}
+
+	sch, err := schemaFor(md)
+	if err != nil {
+		if cfg.strict {
+			return nil, err
+		}
+		warns = append(warns, err)
+	}
+	if !fromMFCG(md) {
+		warns = append(warns, fmt.Errorf("%w: %q", ErrUnknownGenerator, md.Generator))
 	}
 
 	feats := make(map[string]feature)
-	for _, ft := range collect.Features {
-		if ft.ID == "" {
+	var extra []Feature
+	for _, ft := range fts {
+		if cfg.layers != nil && !cfg.layers[ft.ID] && ft.ID != IDValues {
+			continue
+		}
+
+		if !sch.layers[ft.ID] {
+			if cfg.strict {
+				return nil, ft.locate(ErrUnknownLayer)
+			}
+
+			// Unknown features are kept even if their geometry cannot be
+			// decoded, since they are not this package's to validate.
+			raw := collect.Features[ft.index]
+			var ex Feature
+			if err := json.Unmarshal(raw, &ex); err != nil {
+				warns = append(warns, ft.locate(err))
+				ex = Feature{ID: ex.ID, NoID: ex.NoID, Raw: raw}
+			}
+			extra = append(extra, ex)
 			continue
 		}
-		feats[ft.ID] = *ft
+
+		if cfg.strict {
+			if l, _ := findLayer(ft.ID); ft.Type != l.typ {
+				return nil, ft.locate(ErrGeometryType)
+			}
+		}
+
+		prev, ok := feats[ft.ID]
+		if !ok {
+			feats[ft.ID] = ft
+			continue
+		}
+		if cfg.strict {
+			return nil, ft.locate(ErrDuplicateID)
+		}
+
+		merged, err := prev.merge(ft)
+		if err != nil {
+			if !cfg.lenient {
+				return nil, err
+			}
+			warns = append(warns, err)
+			continue
+		}
+		feats[ft.ID] = merged
 	}
 
-	mp, err := toMap(feats)
+	mp, err := toMap(feats, cfg)
 	if err != nil {
 		return nil, err
 	}
+	mp.Extra = extra
+	mp.Warnings = append(warns, mp.Warnings...)
 
 	return mp, nil
 }
+
+// Encode writes the provided Map to w as MFCG data. The Map's Extra features
+// are written after every recognized feature. The output can be read back
+// with New.
+func Encode(w io.Writer, m *Map) error {
+	feats, err := fromMap(m)
+	if err != nil {
+		return err
+	}
+
+	collect := featureCollection{
+		Type:     typeFeatureCollection,
+		Features: make([]interface{}, 0, len(feats)+len(m.Extra)),
+	}
+	for _, ft := range feats {
+		collect.Features = append(collect.Features, ft)
+	}
+	for _, ex := range m.Extra {
+		collect.Features = append(collect.Features, ex)
+	}
+
+	return json.NewEncoder(w).Encode(collect)
+}
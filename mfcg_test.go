@@ -1,11 +1,15 @@
 package mfcg
 
 import (
+	"bytes"
+	"errors"
 	"log"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 const (
@@ -14,6 +18,7 @@ const (
 	testFileInvalid   string = "./test_data/mapInvalidFeature.json"
 	testFileEmpty     string = "./test_data/emptyArray.json"
 	testFileBlank     string = "./test_data/blank.json"
+	testFileTrees     string = "./test_data/mapTrees.json"
 )
 
 func TestNew(t *testing.T) {
@@ -168,9 +173,49 @@ func TestNew(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:    "Missing ID field",
-			file:    testFileMissingID,
-			want:    &Map{},
+			name: "Missing ID field",
+			file: testFileMissingID,
+			want: &Map{
+				Extra: []Feature{
+					{
+						NoID: true,
+						Geometry: Geometry{
+							Type: "Polygon",
+							Coordinates: [][]Point{
+								{
+									{X: -387.597, Y: -107.006},
+									{X: -392.249, Y: -105.345},
+									{X: -435.589, Y: 14.775},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Trees",
+			file: testFileTrees,
+			want: &Map{
+				Greens: []Polygon{
+					{
+						Coords: [][]Point{
+							{
+								{X: -40.415, Y: 60.217},
+								{X: -22.108, Y: 58.844},
+								{X: -25.39, Y: 81.552},
+							},
+						},
+					},
+				},
+				Trees: []Point{
+					{X: -31.172, Y: 64.535},
+					{X: -27.913, Y: 70.218},
+					{X: -35.554, Y: 61.807},
+				},
+				MetaData: fullMap.MetaData,
+			},
 			wantErr: false,
 		},
 		{
@@ -212,3 +257,194 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+	}{
+		{
+			name: "Full map",
+			file: testFileMap,
+		},
+		{
+			name: "Missing ID field",
+			file: testFileMissingID,
+		},
+		{
+			name: "Trees",
+			file: testFileTrees,
+		},
+		{
+			name: "Districts",
+			file: testFileDistricts,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			f, err := os.Open(test.file)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer f.Close()
+
+			want, err := New(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			if err := Encode(&buf, want); err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+
+			got, err := New(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+// testMap returns the Map decoded from the provided test file.
+func testMap(t *testing.T, file string) *Map {
+	t.Helper()
+
+	f, err := os.Open(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	mp, err := New(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return mp
+}
+
+func TestNew_duplicateIDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		opts     []Option
+		want     *Map
+		wantWarn error
+		wantErr  error
+	}{
+		{
+			name: "Merged roads",
+			data: `{"features": [
+				{"type": "GeometryCollection", "id": "roads", "geometries": [
+					{"type": "LineString", "width": 8, "coordinates": [[1, 2], [3, 4]]}
+				]},
+				{"type": "GeometryCollection", "id": "roads", "geometries": [
+					{"type": "LineString", "width": 6, "coordinates": [[3, 4], [5, 6]]}
+				]}
+			]}`,
+			want: &Map{
+				Roads: []LineString{
+					{Width: 8, Coords: []Point{{X: 1, Y: 2}, {X: 3, Y: 4}}},
+					{Width: 6, Coords: []Point{{X: 3, Y: 4}, {X: 5, Y: 6}}},
+				},
+			},
+		},
+		{
+			name: "Merged buildings",
+			data: `{"features": [
+				{"type": "MultiPolygon", "id": "buildings", "coordinates": [[[[1, 1], [2, 1], [2, 2]]]]},
+				{"type": "MultiPolygon", "id": "buildings", "coordinates": []},
+				{"type": "MultiPolygon", "id": "buildings", "coordinates": [[[[5, 5], [6, 5], [6, 6]]]]}
+			]}`,
+			want: &Map{
+				Buildings: []Polygon{
+					{Coords: [][]Point{{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}}}},
+					{Coords: [][]Point{{{X: 5, Y: 5}, {X: 6, Y: 5}, {X: 6, Y: 6}}}},
+				},
+			},
+		},
+		{
+			name: "Duplicate extras",
+			data: `{"features": [
+				{"type": "Point", "id": "well", "coordinates": [1, 2]},
+				{"type": "Point", "id": "well", "coordinates": [3, 4]}
+			]}`,
+			want: &Map{
+				Extra: []Feature{
+					{ID: "well", Geometry: Geometry{Type: "Point", Coordinates: Point{X: 1, Y: 2}}},
+					{ID: "well", Geometry: Geometry{Type: "Point", Coordinates: Point{X: 3, Y: 4}}},
+				},
+			},
+		},
+		{
+			name: "Duplicate earth",
+			data: `{"features": [
+				{"type": "Polygon", "id": "earth", "coordinates": [[[1, 1], [2, 1], [2, 2]]]},
+				{"type": "Polygon", "id": "earth", "coordinates": [[[5, 5], [6, 5], [6, 6]]]}
+			]}`,
+			wantErr: ErrDuplicateID,
+		},
+		{
+			name: "Duplicate values",
+			data: `{"features": [
+				{"type": "Feature", "id": "values", "roadWidth": 8},
+				{"type": "Feature", "id": "values", "roadWidth": 6}
+			]}`,
+			wantErr: ErrDuplicateID,
+		},
+		{
+			name: "Mismatched types",
+			data: `{"features": [
+				{"type": "MultiPolygon", "id": "buildings", "coordinates": []},
+				{"type": "GeometryCollection", "id": "buildings", "geometries": []}
+			]}`,
+			wantErr: ErrDuplicateID,
+		},
+		{
+			name: "Strict roads",
+			data: `{"features": [
+				{"type": "Feature", "id": "values"},
+				{"type": "GeometryCollection", "id": "roads", "geometries": []},
+				{"type": "GeometryCollection", "id": "roads", "geometries": []}
+			]}`,
+			opts:    []Option{Strict()},
+			wantErr: ErrDuplicateID,
+		},
+		{
+			name: "Lenient earth",
+			data: `{"features": [
+				{"type": "Polygon", "id": "earth", "coordinates": [[[1, 1], [2, 1], [2, 2]]]},
+				{"type": "Polygon", "id": "earth", "coordinates": [[[5, 5], [6, 5], [6, 6]]]}
+			]}`,
+			opts: []Option{Lenient()},
+			want: &Map{
+				Earth: Polygon{Coords: [][]Point{{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}}}},
+			},
+			wantWarn: ErrDuplicateID,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NewWithOptions(strings.NewReader(test.data), test.opts...)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got: <%v>, want error: <%v>", err, test.wantErr)
+			}
+
+			if diff := cmp.Diff(got, test.want, cmpopts.IgnoreFields(Map{}, "Warnings")); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+
+			if test.wantWarn != nil && (len(got.Warnings) != 1 || !errors.Is(got.Warnings[0], test.wantWarn)) {
+				t.Errorf("got: <%v>, want: <%v>", got.Warnings, test.wantWarn)
+			}
+		})
+	}
+}
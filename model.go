@@ -0,0 +1,207 @@
+package mfcg
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+)
+
+// groundStep is the height separating successive ground layers of a 3D
+// model, so that overlapping planes do not flicker when rendered.
+const groundStep = 0.01
+
+// ModelOptions describes how a Map is extruded into a 3D model. Heights are
+// in map units.
+type ModelOptions struct {
+	BuildingHeight  float64 // BuildingHeight is the height of every building before variation.
+	HeightVariation float64 // HeightVariation is the largest height added at random to each building.
+	PrismHeight     float64 // PrismHeight is the height of every prism.
+	WallHeight      float64 // WallHeight is the height of the walls.
+	TowerHeight     float64 // TowerHeight is the height of the towers along the walls.
+	Seed            int64   // Seed seeds the random building heights, so equal Seeds build equal models.
+	Style           *Style  // Style provides each layer's color and visibility. A nil Style is replaced by DefaultStyle.
+}
+
+// DefaultModelOptions returns ModelOptions giving a Map's buildings a few
+// storeys of varied height, prisms the height of a temple and walls topped
+// by taller towers.
+func DefaultModelOptions() *ModelOptions {
+	return &ModelOptions{
+		BuildingHeight:  6,
+		HeightVariation: 6,
+		PrismHeight:     24,
+		WallHeight:      16,
+		TowerHeight:     22,
+	}
+}
+
+// model is a Map extruded into 3D space with one mesh per layer. A Point
+// (x, y) at height h becomes the vertex (x, h, y), so the model's y-axis
+// points up and the Map is seen unmirrored from above.
+type model struct {
+	meshes []*modelMesh
+}
+
+// modelMesh is the indexed triangle mesh of a single layer. Triangles are
+// wound counterclockwise when seen from outside the volume they bound.
+type modelMesh struct {
+	name     string
+	color    color.Color
+	vertices [][3]float64
+	indices  []int
+}
+
+// model builds the 3D model of the Map described by the provided
+// ModelOptions. Hidden layers and layers without triangles are left out.
+func (m *Map) model(opts *ModelOptions) *model {
+	if opts == nil {
+		opts = DefaultModelOptions()
+	}
+	s := opts.Style
+	if s == nil {
+		s = DefaultStyle()
+	}
+
+	md := &model{}
+	add := func(mesh *modelMesh) {
+		if len(mesh.indices) > 0 {
+			md.meshes = append(md.meshes, mesh)
+		}
+	}
+
+	// Ground layers are stacked in MFCG's drawing order.
+	type groundLayer struct {
+		id    string
+		style LayerStyle
+		polys []Polygon
+	}
+	var ground []groundLayer
+	if m.Earth.Coords != nil {
+		ground = append(ground, groundLayer{IDEarth, s.Earth, []Polygon{m.Earth}})
+	}
+	ground = append(ground,
+		groundLayer{IDWater, s.Water, m.Water},
+		groundLayer{IDFields, s.Fields, m.Fields},
+		groundLayer{IDGreens, s.Greens, m.Greens},
+		groundLayer{IDRivers, s.Rivers, m.RiverSurfaces()},
+		groundLayer{IDRoads, s.Roads, m.RoadSurfaces()},
+		groundLayer{IDSquares, s.Squares, m.Squares},
+	)
+	for i, g := range ground {
+		if g.style.Hidden {
+			continue
+		}
+		mesh := newModelMesh(g.id, g.style)
+		for _, p := range g.polys {
+			mesh.plane(p, float64(i)*groundStep)
+		}
+		add(mesh)
+	}
+
+	if !s.Walls.Hidden {
+		mesh := newModelMesh(IDWalls, s.Walls)
+		for _, p := range m.Walls {
+			width := m.WallThickness
+			if width == 0 {
+				width = p.Width
+			}
+			for _, ring := range p.Coords {
+				ln := LineString{Width: width, Coords: m.wallPath(ring)}
+				for _, p := range ln.Buffer(BufferOptions{}) {
+					mesh.extrude(p, 0, opts.WallHeight)
+				}
+			}
+		}
+		add(mesh)
+	}
+
+	if !s.Towers.Hidden && m.TowerRadius > 0 {
+		mesh := newModelMesh(idTowers, s.Towers)
+		step := math.Pi / 2 / defaultArcSegments
+		for _, pt := range m.towers() {
+			disc := Polygon{Coords: [][]Point{arc(pt, m.TowerRadius, 0, 2*math.Pi, step)}}
+			mesh.extrude(disc, 0, opts.TowerHeight)
+		}
+		add(mesh)
+	}
+
+	if !s.Buildings.Hidden {
+		rng := rand.New(rand.NewSource(opts.Seed))
+		mesh := newModelMesh(IDBuildings, s.Buildings)
+		for _, p := range m.Buildings {
+			mesh.extrude(p, 0, opts.BuildingHeight+rng.Float64()*opts.HeightVariation)
+		}
+		add(mesh)
+	}
+
+	if !s.Prisms.Hidden {
+		mesh := newModelMesh(IDPrisms, s.Prisms)
+		for _, p := range m.Prisms {
+			mesh.extrude(p, 0, opts.PrismHeight)
+		}
+		add(mesh)
+	}
+
+	return md
+}
+
+// newModelMesh returns an empty mesh for the named layer, colored with the
+// LayerStyle's fill or, failing that, its stroke.
+func newModelMesh(name string, s LayerStyle) *modelMesh {
+	clr := s.Fill
+	if clr == nil {
+		clr = s.Stroke
+	}
+	if clr == nil {
+		clr = color.Gray{Y: 0x80}
+	}
+
+	return &modelMesh{name: name, color: clr}
+}
+
+// plane adds Polygon p to the mesh as a flat surface facing up at the
+// provided height.
+func (mm *modelMesh) plane(p Polygon, height float64) {
+	verts, indices := p.Triangulate()
+	base := len(mm.vertices)
+	for _, pt := range verts {
+		mm.vertices = append(mm.vertices, [3]float64{pt.X, height, pt.Y})
+	}
+
+	// Triangulate winds triangles counterclockwise on a y-up plane, which is
+	// clockwise when seen from above once y becomes depth.
+	for i := 0; i < len(indices); i += 3 {
+		mm.indices = append(mm.indices, base+indices[i], base+indices[i+2], base+indices[i+1])
+	}
+}
+
+// extrude adds Polygon p to the mesh as a solid rising from height bottom to
+// height top, made of a roof and one side per edge. The bottom is left open
+// since it rests on the ground.
+func (mm *modelMesh) extrude(p Polygon, bottom, top float64) {
+	if top <= bottom {
+		return
+	}
+	mm.plane(p, top)
+
+	for i, ring := range p.Coords {
+		// Sides face away from the solid when exteriors are wound
+		// counterclockwise and holes clockwise.
+		pts := orientedRing(ring, i == 0)
+		if len(pts) < 3 {
+			continue
+		}
+
+		for j, a := range pts {
+			b := pts[(j+1)%len(pts)]
+			base := len(mm.vertices)
+			mm.vertices = append(mm.vertices,
+				[3]float64{a.X, bottom, a.Y},
+				[3]float64{b.X, bottom, b.Y},
+				[3]float64{b.X, top, b.Y},
+				[3]float64{a.X, top, a.Y},
+			)
+			mm.indices = append(mm.indices, base, base+3, base+2, base, base+2, base+1)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package mfcg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestModelMesh_extrude(t *testing.T) {
+	tests := []struct {
+		name      string
+		p         Polygon
+		bottom    float64
+		top       float64
+		wantVerts int
+		wantTris  int
+	}{
+		{
+			name:      "Square",
+			p:         square(0, 0, 10),
+			top:       5,
+			wantVerts: 4 + 4*4,
+			wantTris:  2 + 4*2,
+		},
+		{
+			name: "Courtyard",
+			p: Polygon{Coords: [][]Point{
+				{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}},
+				{{X: 4, Y: 4}, {X: 6, Y: 4}, {X: 6, Y: 6}, {X: 4, Y: 6}},
+			}},
+			top:       5,
+			wantVerts: 8 + 8*4,
+			wantTris:  8 + 8*2,
+		},
+		{
+			name:   "No height",
+			p:      square(0, 0, 10),
+			bottom: 5,
+			top:    5,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			mesh := &modelMesh{}
+			mesh.extrude(test.p, test.bottom, test.top)
+
+			if diff := cmp.Diff(len(mesh.vertices), test.wantVerts); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+			if diff := cmp.Diff(len(mesh.indices), 3*test.wantTris); diff != "" {
+				t.Fatalf("mismatch (-got +want):\n%s", diff)
+			}
+
+			// A solid open only at its bottom has a signed volume equal to
+			// the roof's area times its height when its faces point outwards.
+			var volume float64
+			for i := 0; i < len(mesh.indices); i += 3 {
+				a, b, c := mesh.vertices[mesh.indices[i]], mesh.vertices[mesh.indices[i+1]], mesh.vertices[mesh.indices[i+2]]
+				volume += (a[0]*(b[1]*c[2]-b[2]*c[1]) - a[1]*(b[0]*c[2]-b[2]*c[0]) + a[2]*(b[0]*c[1]-b[1]*c[0])) / 6
+			}
+			if want := test.p.Area() * (test.top - test.bottom); test.wantTris > 0 && math.Abs(volume-want) > 1e-9 {
+				t.Errorf("got: <%v>, want: <%v>", volume, want)
+			}
+		})
+	}
+}
+
+func TestMap_model(t *testing.T) {
+	mp := testMap(t, testFileMap)
+
+	hidden := DefaultStyle()
+	hidden.Roads.Hidden = true
+	hidden.Towers.Hidden = true
+
+	tests := []struct {
+		name       string
+		opts       *ModelOptions
+		wantMeshes []string
+	}{
+		{
+			// The fixture's buildings and fields are too thin to triangulate.
+			name: "Default options",
+			opts: nil,
+			wantMeshes: []string{
+				IDEarth, IDWater, IDRivers, IDRoads, IDSquares,
+				IDWalls, idTowers, IDPrisms,
+			},
+		},
+		{
+			name: "Hidden layers",
+			opts: &ModelOptions{BuildingHeight: 6, PrismHeight: 12, WallHeight: 8, TowerHeight: 10, Style: hidden},
+			wantMeshes: []string{
+				IDEarth, IDWater, IDRivers, IDSquares,
+				IDWalls, IDPrisms,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var names []string
+			for _, mesh := range mp.model(test.opts).meshes {
+				names = append(names, mesh.name)
+			}
+
+			if diff := cmp.Diff(names, test.wantMeshes); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMap_model_seed(t *testing.T) {
+	mp := &Map{Buildings: []Polygon{square(0, 0, 10), square(20, 0, 10)}}
+
+	heights := func(seed int64) []float64 {
+		opts := DefaultModelOptions()
+		opts.Seed = seed
+
+		var hs []float64
+		for _, mesh := range mp.model(opts).meshes {
+			if mesh.name != IDBuildings {
+				continue
+			}
+			for _, v := range mesh.vertices {
+				hs = append(hs, v[1])
+			}
+		}
+		return hs
+	}
+
+	if diff := cmp.Diff(heights(1), heights(1)); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+	if cmp.Equal(heights(1), heights(2)) {
+		t.Errorf("got: <%v>, want: <%v>", "equal heights", "heights varying with the seed")
+	}
+}
+
+func TestMap_model_openWalls(t *testing.T) {
+	path := []Point{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 100}}
+	opts := DefaultModelOptions()
+
+	for _, open := range []bool{false, true} {
+		mp := &Map{
+			MetaData:  MetaData{WallThickness: 4},
+			Walls:     []Polygon{{Coords: [][]Point{path}}},
+			OpenWalls: open,
+		}
+
+		var volume float64
+		for _, mesh := range mp.model(opts).meshes {
+			if mesh.name != IDWalls {
+				continue
+			}
+			for i := 0; i < len(mesh.indices); i += 3 {
+				a, b, c := mesh.vertices[mesh.indices[i]], mesh.vertices[mesh.indices[i+1]], mesh.vertices[mesh.indices[i+2]]
+				volume += (a[0]*(b[1]*c[2]-b[2]*c[1]) - a[1]*(b[0]*c[2]-b[2]*c[0]) + a[2]*(b[0]*c[1]-b[1]*c[0])) / 6
+			}
+		}
+
+		ln := LineString{Width: 4, Coords: mp.wallPath(path)}
+		var want float64
+		for _, p := range ln.Buffer(BufferOptions{}) {
+			want += p.Area() * opts.WallHeight
+		}
+		if math.Abs(math.Abs(volume)-want) > 1e-6 {
+			t.Errorf("open %v: got: <%v>, want: <%v>", open, math.Abs(volume), want)
+		}
+	}
+}
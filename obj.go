@@ -0,0 +1,62 @@
+package mfcg
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+)
+
+// WriteOBJ writes the Map to obj as a Wavefront OBJ model extruded using the
+// provided ModelOptions, and writes the model's materials to mtl as an MTL
+// material library. A nil ModelOptions is replaced by DefaultModelOptions.
+// The model holds one object and one material per layer, both named after
+// the layer's ID. If mtlName is not empty, the model refers to its material
+// library by that name, which should be the library's file name. A nil mtl
+// skips the material library.
+func (m *Map) WriteOBJ(obj, mtl io.Writer, mtlName string, opts *ModelOptions) error {
+	md := m.model(opts)
+
+	w := bufio.NewWriter(obj)
+	w.WriteString("# mfcg\n")
+	if mtlName != "" {
+		fmt.Fprintf(w, "mtllib %s\n", mtlName)
+	}
+
+	// OBJ indices are 1-based and shared by every object of the file.
+	offset := 1
+	for _, mesh := range md.meshes {
+		fmt.Fprintf(w, "o %s\nusemtl %s\n", mesh.name, mesh.name)
+		for _, v := range mesh.vertices {
+			fmt.Fprintf(w, "v %s %s %s\n", svgNumber(v[0]), svgNumber(v[1]), svgNumber(v[2]))
+		}
+		for i := 0; i < len(mesh.indices); i += 3 {
+			fmt.Fprintf(w, "f %d %d %d\n", offset+mesh.indices[i], offset+mesh.indices[i+1], offset+mesh.indices[i+2])
+		}
+		offset += len(mesh.vertices)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if mtl == nil {
+		return nil
+	}
+
+	w = bufio.NewWriter(mtl)
+	for i, mesh := range md.meshes {
+		if i > 0 {
+			w.WriteString("\n")
+		}
+
+		c := color.NRGBAModel.Convert(mesh.color).(color.NRGBA)
+		fmt.Fprintf(w, "newmtl %s\n", mesh.name)
+		fmt.Fprintf(w, "Kd %s %s %s\n", svgNumber(float64(c.R)/0xff), svgNumber(float64(c.G)/0xff), svgNumber(float64(c.B)/0xff))
+		if c.A != 0xff {
+			fmt.Fprintf(w, "d %s\n", svgNumber(float64(c.A)/0xff))
+		}
+		w.WriteString("illum 1\n")
+	}
+
+	return w.Flush()
+}
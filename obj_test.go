@@ -0,0 +1,70 @@
+package mfcg
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMap_WriteOBJ(t *testing.T) {
+	mp := testMap(t, testFileMap)
+
+	var obj, mtl bytes.Buffer
+	if err := mp.WriteOBJ(&obj, &mtl, "map.mtl", nil); err != nil {
+		t.Fatalf("got: <%v>, want error: <%v>", err, false)
+	}
+
+	var objects, materials, libs []string
+	verts := 0
+	sc := bufio.NewScanner(&obj)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "mtllib":
+			libs = append(libs, fields[1])
+		case "o":
+			objects = append(objects, fields[1])
+		case "usemtl":
+			materials = append(materials, fields[1])
+		case "v":
+			verts++
+		case "f":
+			for _, f := range fields[1:] {
+				n, err := strconv.Atoi(f)
+				if err != nil || n < 1 || n > verts {
+					t.Errorf("got: <%v>, want: <%s>", f, "index of a previous vertex")
+				}
+			}
+		}
+	}
+
+	want := []string{IDEarth, IDWater, IDRivers, IDRoads, IDSquares, IDWalls, idTowers, IDPrisms}
+	if diff := cmp.Diff(libs, []string{"map.mtl"}); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+	if diff := cmp.Diff(objects, want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+	if diff := cmp.Diff(materials, want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	var defined []string
+	sc = bufio.NewScanner(&mtl)
+	for sc.Scan() {
+		if name := strings.TrimPrefix(sc.Text(), "newmtl "); name != sc.Text() {
+			defined = append(defined, name)
+		}
+	}
+	if diff := cmp.Diff(defined, want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
@@ -0,0 +1,78 @@
+package mfcg
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	// ErrUnknownLayer is returned when a layer ID is not recognized, such as
+	// in strict mode when a feature's ID is not exported by the version of
+	// MFCG that wrote the data.
+	ErrUnknownLayer = errors.New("unknown layer")
+	// ErrDuplicateID is returned when a feature's ID is used by an earlier
+	// feature and the two cannot be combined.
+	ErrDuplicateID = errors.New("duplicate feature ID")
+	// ErrMissingValues is returned in strict mode when the data has no values
+	// feature.
+	ErrMissingValues = errors.New("missing values feature")
+	// ErrGeometryType is returned in strict mode when a feature's geometry
+	// type does not match the one MFCG exports for its layer.
+	ErrGeometryType = errors.New("unexpected geometry type")
+)
+
+// config controls how MFCG data is decoded.
+type config struct {
+	strict  bool            // strict rejects data which deviates from MFCG's schema.
+	lenient bool            // lenient reports recoverable problems as warnings.
+	layers  map[string]bool // layers restricts decoding to the contained IDs, if not nil.
+}
+
+// Option configures how NewWithOptions decodes MFCG data.
+type Option func(*config)
+
+// Strict rejects data which deviates in any way from what MFCG exports.
+func Strict() Option {
+	return func(cfg *config) {
+		cfg.strict = true
+		cfg.lenient = false
+	}
+}
+
+// Lenient skips features which cannot be decoded, reporting them as warnings.
+func Lenient() Option {
+	return func(cfg *config) {
+		cfg.lenient = true
+		cfg.strict = false
+	}
+}
+
+// Layers decodes only the features with the provided IDs. Every other
+// feature, including unrecognized ones, is skipped without being decoded. The
+// values feature is always decoded.
+func Layers(ids ...string) Option {
+	return func(cfg *config) {
+		if cfg.layers == nil {
+			cfg.layers = make(map[string]bool)
+		}
+		for _, id := range ids {
+			cfg.layers[id] = true
+		}
+	}
+}
+
+// NewWithOptions reads the provided MFCG data from r according to the
+// provided options and returns the corresponding Map. By default, features
+// which are not recognized are kept in the Map's Extra field, features
+// sharing the ID of a collection layer such as roads are merged, and data
+// from an unsupported version or generator is decoded as the newest supported
+// version with a warning. Whichever of Strict and Lenient is passed last
+// takes effect.
+func NewWithOptions(r io.Reader, opts ...Option) (*Map, error) {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return decode(r, cfg)
+}
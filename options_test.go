@@ -0,0 +1,181 @@
+package mfcg
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewWithOptions_strict(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{
+			name: "Valid data",
+			data: `{"features": [
+				{"type": "Feature", "id": "values", "generator": "mfcg", "version": "0.7.7a"},
+				{"type": "MultiPolygon", "id": "buildings", "coordinates": [[[[1, 2], [3, 4], [5, 6]]]]}
+			]}`,
+			wantErr: nil,
+		},
+		{
+			name: "Missing values",
+			data: `{"features": [
+				{"type": "MultiPolygon", "id": "buildings", "coordinates": []}
+			]}`,
+			wantErr: ErrMissingValues,
+		},
+		{
+			name: "Unknown ID",
+			data: `{"features": [
+				{"type": "Feature", "id": "values"},
+				{"type": "MultiPoint", "id": "lanterns", "coordinates": []}
+			]}`,
+			wantErr: ErrUnknownLayer,
+		},
+		{
+			name: "Missing ID",
+			data: `{"features": [
+				{"type": "Feature", "id": "values"},
+				{"type": "MultiPoint", "coordinates": []}
+			]}`,
+			wantErr: ErrUnknownLayer,
+		},
+		{
+			name: "Duplicate ID",
+			data: `{"features": [
+				{"type": "Feature", "id": "values"},
+				{"type": "MultiPolygon", "id": "buildings", "coordinates": []},
+				{"type": "MultiPolygon", "id": "buildings", "coordinates": []}
+			]}`,
+			wantErr: ErrDuplicateID,
+		},
+		{
+			name: "Wrong geometry type",
+			data: `{"features": [
+				{"type": "Feature", "id": "values"},
+				{"type": "GeometryCollection", "id": "buildings", "geometries": []}
+			]}`,
+			wantErr: ErrGeometryType,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewWithOptions(strings.NewReader(test.data), Strict())
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got: <%v>, want error: <%v>", err, test.wantErr)
+			}
+
+			var pe *ParseError
+			if err != nil && !errors.As(err, &pe) {
+				t.Errorf("got: <%T>, want: <%T>", err, pe)
+			}
+		})
+	}
+}
+
+func TestNewWithOptions_strictFixture(t *testing.T) {
+	f, err := os.Open(testFileMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err := NewWithOptions(f, Strict()); err != nil {
+		t.Errorf("got: <%v>, want error: <%v>", err, false)
+	}
+}
+
+func TestNewWithOptions_lenient(t *testing.T) {
+	data := `{"features": [
+		{"type": "Feature", "id": "values", "roadWidth": 8},
+		{"type": "Polygon", "id": "earth", "coordinates": [[["foo", "bar"]]]},
+		{"type": "MultiPoint", "id": 5},
+		{"type": "GeometryCollection", "id": "roads", "geometries": [
+			{"type": "LineString", "width": 8, "coordinates": [[1, 2], [3, 4]]}
+		]}
+	]}`
+
+	mp, err := NewWithOptions(strings.NewReader(data), Lenient())
+	if err != nil {
+		t.Fatalf("got: <%v>, want error: <%v>", err, false)
+	}
+
+	want := []LineString{{Width: 8, Coords: []Point{{X: 1, Y: 2}, {X: 3, Y: 4}}}}
+	if diff := cmp.Diff(mp.Roads, want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(mp.Earth, Polygon{}); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	var got []int
+	for _, w := range mp.Warnings {
+		var pe *ParseError
+		if !errors.As(w, &pe) {
+			t.Fatalf("got: <%T>, want: <%T>", w, pe)
+		}
+		got = append(got, pe.Feature)
+	}
+
+	if diff := cmp.Diff(got, []int{2, 1}); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestNewWithOptions_precedence(t *testing.T) {
+	data := `{"features": [
+		{"type": "Feature", "id": "values", "roadWidth": 8},
+		{"type": "MultiPoint", "id": "lanterns", "coordinates": [[1, 2]]}
+	]}`
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+	}{
+		{name: "Strict last", opts: []Option{Lenient(), Strict()}, wantErr: ErrUnknownLayer},
+		{name: "Lenient last", opts: []Option{Strict(), Lenient()}, wantErr: nil},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewWithOptions(strings.NewReader(data), test.opts...)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("got: <%v>, want error: <%v>", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewWithOptions_layers(t *testing.T) {
+	f, err := os.Open(testFileMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	mp, err := NewWithOptions(f, Layers(IDRoads, IDPlanks))
+	if err != nil {
+		t.Fatalf("got: <%v>, want error: <%v>", err, false)
+	}
+
+	full := testMap(t, testFileMap)
+	want := &Map{
+		MetaData: full.MetaData,
+		Planks:   full.Planks,
+		Roads:    full.Roads,
+	}
+
+	if diff := cmp.Diff(mp, want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
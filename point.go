@@ -3,11 +3,21 @@ package mfcg
 import (
 	"encoding/json"
 	"errors"
+	"math"
 )
 
 // pointSliceLength equals the length of MFCG's coordinate arrays.
 const pointSliceLength = 2
 
+var (
+	// ErrPointLength is returned when Point data is not of length 2.
+	ErrPointLength = errors.New("expecting Point data to conform to a slice of float64's of length 2")
+	// ErrPointX is returned when Point data holds a non-numeric X coordinate.
+	ErrPointX = errors.New("expecting float64 for Point's X field")
+	// ErrPointY is returned when Point data holds a non-numeric Y coordinate.
+	ErrPointY = errors.New("expecting float64 for Point's Y field")
+)
+
 // Point contains the coordinates of a point on a cartesian plane.
 type Point struct {
 	X float64
@@ -23,16 +33,114 @@ func (p *Point) UnmarshalJSON(data []byte) error {
 	}
 
 	if len(points) != pointSliceLength {
-		return errors.New("expecting Point data to conform to a slice of float64's of length 2")
+		return ErrPointLength
 	}
 
 	var ok bool
 	if p.X, ok = points[0].(float64); !ok {
-		return errors.New("expecting float64 for Point's X field")
+		return ErrPointX
 	}
 	if p.Y, ok = points[1].(float64); !ok {
-		return errors.New("expecting float64 for Point's Y field")
+		return ErrPointY
 	}
 
 	return nil
 }
+
+// decodePoints returns the Points corresponding to the provided coordinate
+// data. The data must conform to a slice of Points. A failure is reported as
+// a *ParseError locating the failing Point.
+func decodePoints(data []byte) ([]Point, error) {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return nil, err
+	}
+	if raws == nil {
+		return nil, nil
+	}
+
+	pts := make([]Point, len(raws))
+	for i, raw := range raws {
+		if err := json.Unmarshal(raw, &pts[i]); err != nil {
+			pe := locate(err)
+			pe.Point = i
+			return nil, pe
+		}
+	}
+
+	return pts, nil
+}
+
+// coordsToPoints returns a slice of Points corresponding to the provided
+// coordinate data. The data must conform to a slice of Points.
+func coordsToPoints(data []byte) ([]Point, error) {
+	pts, err := decodePoints(data)
+	if err != nil {
+		return nil, err
+	}
+	if pts == nil {
+		pts = []Point{}
+	}
+
+	return pts, nil
+}
+
+// pointsToCoords returns the coordinate data corresponding to the provided
+// Points. The data conforms to a slice of Points.
+func pointsToCoords(pts []Point) ([]byte, error) {
+	return json.Marshal(pts)
+}
+
+// MarshalJSON encodes the X and Y coordinates of a Point as a slice of
+// float64's of length 2.
+func (p Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal([pointSliceLength]float64{p.X, p.Y})
+}
+
+// distance returns the euclidean distance between Points a and b.
+func distance(a, b Point) float64 {
+	return math.Hypot(b.X-a.X, b.Y-a.Y)
+}
+
+// segmentDistance returns the euclidean distance between Point p and the
+// segment from a to b.
+func segmentDistance(p, a, b Point) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	if dx == 0 && dy == 0 {
+		return distance(p, a)
+	}
+
+	t := ((p.X-a.X)*dx + (p.Y-a.Y)*dy) / (dx*dx + dy*dy)
+	t = math.Max(0, math.Min(1, t))
+
+	return distance(p, Point{X: a.X + t*dx, Y: a.Y + t*dy})
+}
+
+// orient returns the cross product of the vectors from o to a and from o to
+// b. It is positive if o, a and b turn counterclockwise on a y-up plane,
+// negative if they turn clockwise and zero if they are collinear.
+func orient(o, a, b Point) float64 {
+	return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+}
+
+// segmentsIntersect reports whether the segment from a to b and the segment
+// from c to d share at least one Point.
+func segmentsIntersect(a, b, c, d Point) bool {
+	d1, d2 := orient(c, d, a), orient(c, d, b)
+	d3, d4 := orient(a, b, c), orient(a, b, d)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+
+	return (d1 == 0 && onSegment(a, c, d)) ||
+		(d2 == 0 && onSegment(b, c, d)) ||
+		(d3 == 0 && onSegment(c, a, b)) ||
+		(d4 == 0 && onSegment(d, a, b))
+}
+
+// onSegment reports whether Point p, known to be collinear with a and b, lies
+// between them.
+func onSegment(p, a, b Point) bool {
+	return math.Min(a.X, b.X) <= p.X && p.X <= math.Max(a.X, b.X) &&
+		math.Min(a.Y, b.Y) <= p.Y && p.Y <= math.Max(a.Y, b.Y)
+}
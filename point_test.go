@@ -90,3 +90,40 @@ func TestPoint_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestPoint_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		point Point
+		want  string
+	}{
+		{
+			name:  "Positive coordinates",
+			point: Point{X: 12.3, Y: 45.6},
+			want:  `[12.3,45.6]`,
+		},
+		{
+			name:  "Negative coordinates",
+			point: Point{X: -387.597, Y: -107.006},
+			want:  `[-387.597,-107.006]`,
+		},
+		{
+			name:  "Zero value",
+			point: Point{},
+			want:  `[0,0]`,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := json.Marshal(test.point)
+			if err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+
+			if diff := cmp.Diff(string(got), test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package mfcg
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"math"
+)
 
 // Polygon is an area within a set of Points.
 type Polygon struct {
@@ -11,27 +14,33 @@ type Polygon struct {
 // coordsToPolygon returns a Polygon corresponding to the provided coordinate
 // data. The data must conform to a 2D slice of Points.
 func coordsToPolygon(data []byte) (*Polygon, error) {
-	var points [][]Point
-	if err := json.Unmarshal(data, &points); err != nil {
+	rings, err := decodeRings(data)
+	if err != nil {
 		return nil, err
 	}
 
 	return &Polygon{
-		Coords: points,
+		Coords: rings,
 	}, nil
 }
 
 // coordsToPolygons returns a slice of Polygons each corresponding to the
 // provided coordinate data. The data must conform to a 3D slice of Points.
 func coordsToPolygons(data []byte) ([]Polygon, error) {
-	var points [][][]Point
-	if err := json.Unmarshal(data, &points); err != nil {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
 		return nil, err
 	}
 
-	polys := make([]Polygon, len(points))
-	for i := range points {
-		polys[i] = Polygon{Coords: points[i]}
+	polys := make([]Polygon, len(raws))
+	for i, raw := range raws {
+		rings, err := decodeRings(raw)
+		if err != nil {
+			pe := locate(err)
+			pe.Geometry = i
+			return nil, pe
+		}
+		polys[i] = Polygon{Coords: rings}
 	}
 
 	return polys, nil
@@ -41,20 +50,237 @@ func coordsToPolygons(data []byte) ([]Polygon, error) {
 // provided GeometryCollection data. The data must conform to a slice of MFCG's
 // proprietary polygon geometries.
 func geosToPolygons(data []byte) ([]Polygon, error) {
-	var geos []*json.RawMessage
+	var geos []json.RawMessage
 	if err := json.Unmarshal(data, &geos); err != nil {
 		return nil, err
 	}
 
 	var polys []Polygon
-	for _, g := range geos {
-		var p Polygon
-		if err := json.Unmarshal(*g, &p); err != nil {
-			return nil, err
+	for i, g := range geos {
+		p, err := geoToPolygon(g)
+		if err != nil {
+			pe := locate(err)
+			pe.Geometry = i
+			return nil, pe
 		}
 
-		polys = append(polys, p)
+		polys = append(polys, *p)
 	}
 
 	return polys, nil
 }
+
+// geoToPolygon returns a Polygon corresponding to the provided geometry
+// data. The data must conform to one of MFCG's proprietary polygon
+// geometries.
+func geoToPolygon(data []byte) (*Polygon, error) {
+	var geo struct {
+		Width       float64         `json:"width"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+	if err := json.Unmarshal(data, &geo); err != nil {
+		return nil, err
+	}
+
+	if geo.Coordinates == nil {
+		return &Polygon{Width: geo.Width}, nil
+	}
+
+	rings, err := decodeRings(geo.Coordinates)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Polygon{Width: geo.Width, Coords: rings}, nil
+}
+
+// decodeRings returns the rings corresponding to the provided coordinate
+// data. The data must conform to a 2D slice of Points. A failure is reported
+// as a *ParseError locating the failing ring and Point.
+func decodeRings(data []byte) ([][]Point, error) {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return nil, err
+	}
+	if raws == nil {
+		return nil, nil
+	}
+
+	rings := make([][]Point, len(raws))
+	for i, raw := range raws {
+		pts, err := decodePoints(raw)
+		if err != nil {
+			pe := locate(err)
+			pe.Ring = i
+			return nil, pe
+		}
+		rings[i] = pts
+	}
+
+	return rings, nil
+}
+
+// polygonToCoords returns the coordinate data corresponding to the provided
+// Polygon. The data conforms to a 2D slice of Points.
+func polygonToCoords(p Polygon) ([]byte, error) {
+	return json.Marshal(p.Coords)
+}
+
+// polygonsToCoords returns the coordinate data corresponding to the provided
+// Polygons. The data conforms to a 3D slice of Points.
+func polygonsToCoords(polys []Polygon) ([]byte, error) {
+	points := make([][][]Point, len(polys))
+	for i := range polys {
+		points[i] = polys[i].Coords
+	}
+
+	return json.Marshal(points)
+}
+
+// polygonsToGeos returns the GeometryCollection data corresponding to the
+// provided Polygons. The data conforms to a slice of MFCG's proprietary
+// polygon geometries.
+func polygonsToGeos(polys []Polygon) ([]byte, error) {
+	geos := make([]geometry, len(polys))
+	for i, p := range polys {
+		geos[i] = geometry{
+			Type:        typePolygon,
+			Width:       p.Width,
+			Coordinates: p.Coords,
+		}
+	}
+
+	return json.Marshal(geos)
+}
+
+// Area returns the area of the Polygon. The first ring is the Polygon's
+// exterior and the area of any following ring is subtracted as a hole.
+func (p Polygon) Area() float64 {
+	var area float64
+	for i, ring := range p.Coords {
+		a := math.Abs(signedArea(ring))
+		if i > 0 {
+			a = -a
+		}
+		area += a
+	}
+
+	return area
+}
+
+// Perimeter returns the total length of the Polygon's rings, including the
+// rings of any holes.
+func (p Polygon) Perimeter() float64 {
+	var length float64
+	for _, ring := range p.Coords {
+		length += pathLength(ring)
+		if len(ring) > 1 {
+			length += distance(ring[len(ring)-1], ring[0])
+		}
+	}
+
+	return length
+}
+
+// Centroid returns the center of mass of the Polygon, taking holes into
+// account. The centroid of a Polygon without area is the average of its
+// exterior's Points.
+func (p Polygon) Centroid() Point {
+	var area, cx, cy float64
+	for i, ring := range p.Coords {
+		ringArea := signedArea(ring)
+		sign := 1.0
+		if (ringArea < 0) != (i > 0) {
+			sign = -1
+		}
+
+		for j := range ring {
+			a, b := ring[j], ring[(j+1)%len(ring)]
+			cross := a.X*b.Y - b.X*a.Y
+			cx += sign * (a.X + b.X) * cross
+			cy += sign * (a.Y + b.Y) * cross
+		}
+		area += sign * ringArea
+	}
+
+	if area == 0 {
+		if len(p.Coords) == 0 || len(p.Coords[0]) == 0 {
+			return Point{}
+		}
+
+		var sum Point
+		for _, pt := range p.Coords[0] {
+			sum.X += pt.X
+			sum.Y += pt.Y
+		}
+		n := float64(len(p.Coords[0]))
+		return Point{X: sum.X / n, Y: sum.Y / n}
+	}
+
+	return Point{X: cx / (6 * area), Y: cy / (6 * area)}
+}
+
+// Bounds returns the smallest Rect containing every ring of the Polygon.
+func (p Polygon) Bounds() Rect {
+	r := emptyRect()
+	for _, ring := range p.Coords {
+		r = r.extend(ring)
+	}
+
+	return r
+}
+
+// signedArea returns the area of the provided ring. The area is positive if
+// the ring's Points are ordered counterclockwise on a y-up plane and
+// negative otherwise.
+func signedArea(ring []Point) float64 {
+	var sum float64
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		sum += a.X*b.Y - b.X*a.Y
+	}
+
+	return sum / 2
+}
+
+// Contains reports whether Point pt lies within the Polygon. Points within
+// a hole of the Polygon are not contained by it.
+func (p Polygon) Contains(pt Point) bool {
+	inside := false
+	for _, ring := range p.Coords {
+		if ringContains(ring, pt) {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// Distance returns the euclidean distance between Point pt and the
+// Polygon. The distance is zero if the Polygon contains pt.
+func (p Polygon) Distance(pt Point) float64 {
+	if p.Contains(pt) {
+		return 0
+	}
+
+	d := math.Inf(1)
+	for _, ring := range p.Coords {
+		d = math.Min(d, ringDistance(ring, pt))
+	}
+
+	return d
+}
+
+// ringContains reports whether Point pt lies within the provided ring using
+// the even-odd rule.
+func ringContains(ring []Point, pt Point) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Y > pt.Y) != (b.Y > pt.Y) && pt.X < (b.X-a.X)*(pt.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
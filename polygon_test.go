@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func Test_coordsToPolygon(t *testing.T) {
@@ -221,3 +222,240 @@ func Test_geosToPolygons(t *testing.T) {
 		})
 	}
 }
+
+func TestPolygon_Area(t *testing.T) {
+	tests := []struct {
+		name string
+		poly Polygon
+		want float64
+	}{
+		{
+			name: "Counterclockwise square",
+			poly: Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}}}},
+			want: 16,
+		},
+		{
+			name: "Clockwise square",
+			poly: Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 0, Y: 4}, {X: 4, Y: 4}, {X: 4, Y: 0}}}},
+			want: 16,
+		},
+		{
+			name: "Square with holes",
+			poly: Polygon{Coords: [][]Point{
+				{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
+				{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 1, Y: 2}},
+				{{X: 3, Y: 3}, {X: 3, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 3}},
+			}},
+			want: 14,
+		},
+		{
+			name: "Closed ring",
+			poly: Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 3}, {X: 0, Y: 0}}}},
+			want: 6,
+		},
+		{
+			name: "Line",
+			poly: Polygon{Coords: [][]Point{{{X: 45.922, Y: 13.742}, {X: 45.238, Y: 5.889}}}},
+			want: 0,
+		},
+		{
+			name: "No rings",
+			poly: Polygon{},
+			want: 0,
+		},
+		{
+			name: "Test data earth",
+			poly: testMap(t, testFileMap).Earth,
+			want: 243.40525,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := test.poly.Area()
+			if diff := cmp.Diff(got, test.want, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPolygon_Perimeter(t *testing.T) {
+	tests := []struct {
+		name string
+		poly Polygon
+		want float64
+	}{
+		{
+			name: "Open triangle",
+			poly: Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 3}}}},
+			want: 12,
+		},
+		{
+			name: "Closed triangle",
+			poly: Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 3}, {X: 0, Y: 0}}}},
+			want: 12,
+		},
+		{
+			name: "Square with hole",
+			poly: Polygon{Coords: [][]Point{
+				{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
+				{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 1, Y: 2}},
+			}},
+			want: 20,
+		},
+		{
+			name: "No rings",
+			poly: Polygon{},
+			want: 0,
+		},
+		{
+			name: "Test data earth",
+			poly: testMap(t, testFileMap).Earth,
+			want: 263.53547849648044,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := test.poly.Perimeter()
+			if diff := cmp.Diff(got, test.want, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPolygon_Centroid(t *testing.T) {
+	tests := []struct {
+		name string
+		poly Polygon
+		want Point
+	}{
+		{
+			name: "Square",
+			poly: Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 0, Y: 4}, {X: 4, Y: 4}, {X: 4, Y: 0}}}},
+			want: Point{X: 2, Y: 2},
+		},
+		{
+			name: "Square with off-center hole",
+			poly: Polygon{Coords: [][]Point{
+				{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
+				{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 4}, {X: 0, Y: 4}},
+			}},
+			want: Point{X: 3, Y: 2},
+		},
+		{
+			name: "Line",
+			poly: Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 4, Y: 2}}}},
+			want: Point{X: 2, Y: 1},
+		},
+		{
+			name: "No rings",
+			poly: Polygon{},
+			want: Point{},
+		},
+		{
+			name: "Test data earth",
+			poly: testMap(t, testFileMap).Earth,
+			want: Point{X: -405.145, Y: -65.858666666666},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := test.poly.Centroid()
+			if diff := cmp.Diff(got, test.want, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPolygon_Bounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		poly      Polygon
+		want      Rect
+		wantEmpty bool
+	}{
+		{
+			name: "Square with hole",
+			poly: Polygon{Coords: [][]Point{
+				{{X: -1, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 5}},
+				{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}},
+			}},
+			want: Rect{Min: Point{X: -1, Y: 0}, Max: Point{X: 4, Y: 5}},
+		},
+		{
+			name:      "No rings",
+			poly:      Polygon{},
+			want:      emptyRect(),
+			wantEmpty: true,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := test.poly.Bounds()
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+
+			if got.Empty() != test.wantEmpty {
+				t.Errorf("got: <%v>, want empty: <%v>", got.Empty(), test.wantEmpty)
+			}
+		})
+	}
+}
+
+func TestPolygon_Contains(t *testing.T) {
+	poly := Polygon{Coords: [][]Point{
+		{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
+		{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 1, Y: 2}},
+	}}
+	tests := []struct {
+		name string
+		pt   Point
+		want bool
+	}{
+		{name: "Inside", pt: Point{X: 3, Y: 3}, want: true},
+		{name: "Inside hole", pt: Point{X: 1.5, Y: 1.5}, want: false},
+		{name: "Outside", pt: Point{X: 5, Y: 3}, want: false},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := poly.Contains(test.pt)
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPolygon_Distance(t *testing.T) {
+	poly := Polygon{Coords: [][]Point{
+		{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
+		{{X: 1, Y: 1}, {X: 3, Y: 1}, {X: 3, Y: 3}, {X: 1, Y: 3}},
+	}}
+	tests := []struct {
+		name string
+		pt   Point
+		want float64
+	}{
+		{name: "Inside", pt: Point{X: 0.5, Y: 2}, want: 0},
+		{name: "Inside hole", pt: Point{X: 2, Y: 1.5}, want: 0.5},
+		{name: "Outside edge", pt: Point{X: 7, Y: 2}, want: 3},
+		{name: "Outside corner", pt: Point{X: 7, Y: 8}, want: 5},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := poly.Distance(test.pt)
+			if diff := cmp.Diff(got, test.want, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,354 @@
+package mfcg
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+)
+
+// subsamples is the number of scanlines sampled per row of pixels when
+// rasterizing. Coverage along each scanline is computed exactly.
+const subsamples = 4
+
+// ErrImageSize is returned when an image is requested with a width or height
+// which is not positive.
+var ErrImageSize = errors.New("expecting image width and height to be positive")
+
+// Rasterize draws the Map onto a new image of the provided size using the
+// provided Style. A nil Style is replaced by DefaultStyle. The Map is scaled
+// uniformly to fit the image and centered within it. A negative width or
+// height is treated as zero.
+func (m *Map) Rasterize(s *Style, width, height int) *image.RGBA {
+	r := m.extent()
+	dx, dy := r.Dx(), r.Dy()
+
+	scale := 1.0
+	if dx > 0 && dy > 0 {
+		scale = math.Min(float64(width)/dx, float64(height)/dy)
+	}
+
+	origin := Point{
+		X: r.Min.X - (float64(width)/scale-dx)/2,
+		Y: r.Min.Y - (float64(height)/scale-dy)/2,
+	}
+
+	return m.rasterize(s, origin, scale, width, height)
+}
+
+// RasterizeScale draws the Map onto a new image at the provided scale, given
+// in pixels per map unit, using the provided Style. A nil Style is replaced
+// by DefaultStyle. The image is sized to span every feature of the Map, and
+// is empty if the scale is not positive.
+func (m *Map) RasterizeScale(s *Style, scale float64) *image.RGBA {
+	r := m.extent()
+	width := int(math.Ceil(r.Dx() * scale))
+	height := int(math.Ceil(r.Dy() * scale))
+
+	return m.rasterize(s, r.Min, scale, width, height)
+}
+
+// WritePNG writes the Map to w as a PNG image of the provided size drawn
+// using the provided Style. A nil Style is replaced by DefaultStyle. If the
+// width or height is not positive, ErrImageSize is returned.
+func (m *Map) WritePNG(w io.Writer, s *Style, width, height int) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("%w: %dx%d", ErrImageSize, width, height)
+	}
+
+	return png.Encode(w, m.Rasterize(s, width, height))
+}
+
+// rasterize draws the Map onto a new image of the provided size. The map
+// Point origin is drawn at the image's top left corner and every map unit
+// spans scale pixels. A negative width or height is treated as zero.
+func (m *Map) rasterize(s *Style, origin Point, scale float64, width, height int) *image.RGBA {
+	if s == nil {
+		s = DefaultStyle()
+	}
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	if s.Background != nil {
+		draw.Draw(img, img.Bounds(), image.NewUniform(s.Background), image.Point{}, draw.Src)
+	}
+
+	c := &rasterCanvas{
+		img:    img,
+		origin: origin,
+		scale:  scale,
+		cover:  make([]float64, width+1),
+	}
+	m.draw(c, s)
+
+	return img
+}
+
+// rasterCanvas is a canvas drawing anti-aliased shapes onto an image.
+type rasterCanvas struct {
+	img    *image.RGBA
+	origin Point
+	scale  float64
+	style  LayerStyle
+	cover  []float64
+}
+
+// layer implements canvas.
+func (c *rasterCanvas) layer(id string, s LayerStyle) {
+	c.style = s
+}
+
+// polygon implements canvas.
+func (c *rasterCanvas) polygon(rings [][]Point) {
+	paths := make([][]Point, len(rings))
+	for i, ring := range rings {
+		paths[i] = c.project(ring)
+	}
+
+	if c.style.Fill != nil {
+		c.fill(paths, c.style.Fill, false)
+	}
+
+	if c.style.Stroke != nil && c.style.StrokeWidth > 0 {
+		var pieces [][]Point
+		for _, path := range paths {
+			pieces = append(pieces, strokePieces(closeRing(path), c.style.StrokeWidth*c.scale)...)
+		}
+		c.fill(pieces, c.style.Stroke, true)
+	}
+}
+
+// polyline implements canvas.
+func (c *rasterCanvas) polyline(pts []Point, width float64) {
+	if c.style.Stroke == nil || width <= 0 {
+		return
+	}
+
+	c.fill(strokePieces(c.project(pts), width*c.scale), c.style.Stroke, true)
+}
+
+// circle implements canvas.
+func (c *rasterCanvas) circle(pt Point, radius float64) {
+	center := c.project([]Point{pt})[0]
+	disc := circlePath(center, radius*c.scale)
+
+	if c.style.Fill != nil {
+		c.fill([][]Point{disc}, c.style.Fill, false)
+	}
+
+	if c.style.Stroke != nil && c.style.StrokeWidth > 0 {
+		c.fill(strokePieces(closeRing(disc), c.style.StrokeWidth*c.scale), c.style.Stroke, true)
+	}
+}
+
+// project returns the provided map Points in pixel space.
+func (c *rasterCanvas) project(pts []Point) []Point {
+	proj := make([]Point, len(pts))
+	for i, pt := range pts {
+		proj[i] = Point{
+			X: (pt.X - c.origin.X) * c.scale,
+			Y: (pt.Y - c.origin.Y) * c.scale,
+		}
+	}
+
+	return proj
+}
+
+// edge is a non-horizontal line segment of a path, directed downwards.
+type edge struct {
+	x0, y0, x1, y1 float64
+	dir            int
+}
+
+// crossing is the intersection of an edge with a scanline.
+type crossing struct {
+	x   float64
+	dir int
+}
+
+// fill composites clr onto the image within the provided closed paths,
+// given in pixel space. Paths are filled using the non-zero winding rule if
+// nonZero is set and the even-odd rule otherwise.
+func (c *rasterCanvas) fill(paths [][]Point, clr color.Color, nonZero bool) {
+	var edges []edge
+	top, bottom := math.Inf(1), math.Inf(-1)
+	for _, path := range paths {
+		for i := range path {
+			a, b := path[i], path[(i+1)%len(path)]
+			if a.Y == b.Y {
+				continue
+			}
+
+			e := edge{x0: a.X, y0: a.Y, x1: b.X, y1: b.Y, dir: 1}
+			if a.Y > b.Y {
+				e = edge{x0: b.X, y0: b.Y, x1: a.X, y1: a.Y, dir: -1}
+			}
+			edges = append(edges, e)
+			top, bottom = math.Min(top, e.y0), math.Max(bottom, e.y1)
+		}
+	}
+	if len(edges) == 0 {
+		return
+	}
+
+	bounds := c.img.Bounds()
+	minY := int(math.Max(math.Floor(top), float64(bounds.Min.Y)))
+	maxY := int(math.Min(math.Ceil(bottom), float64(bounds.Max.Y)))
+	width := bounds.Dx()
+
+	var xs []crossing
+	for y := minY; y < maxY; y++ {
+		for i := range c.cover {
+			c.cover[i] = 0
+		}
+		touched := false
+
+		for s := 0; s < subsamples; s++ {
+			sy := float64(y) + (float64(s)+0.5)/subsamples
+
+			xs = xs[:0]
+			for _, e := range edges {
+				if sy < e.y0 || sy >= e.y1 {
+					continue
+				}
+				x := e.x0 + (sy-e.y0)*(e.x1-e.x0)/(e.y1-e.y0)
+				xs = append(xs, crossing{x: x, dir: e.dir})
+			}
+			if len(xs) < 2 {
+				continue
+			}
+			sort.Slice(xs, func(i, j int) bool { return xs[i].x < xs[j].x })
+
+			wind := 0
+			for i := 0; i < len(xs)-1; i++ {
+				if nonZero {
+					wind += xs[i].dir
+				} else {
+					wind ^= 1
+				}
+				if wind != 0 {
+					addSpan(c.cover[:width], xs[i].x, xs[i+1].x, 1.0/subsamples)
+					touched = true
+				}
+			}
+		}
+
+		if touched {
+			c.composite(y, clr)
+		}
+	}
+}
+
+// addSpan adds weight to the coverage of each pixel between x0 and x1,
+// proportionally to the part of the pixel the span covers.
+func addSpan(cover []float64, x0, x1, weight float64) {
+	x0 = math.Max(x0, 0)
+	x1 = math.Min(x1, float64(len(cover)))
+	if x0 >= x1 {
+		return
+	}
+
+	i, j := int(x0), int(x1)
+	if i == j {
+		cover[i] += (x1 - x0) * weight
+		return
+	}
+
+	cover[i] += (float64(i+1) - x0) * weight
+	for k := i + 1; k < j; k++ {
+		cover[k] += weight
+	}
+	if j < len(cover) {
+		cover[j] += (x1 - float64(j)) * weight
+	}
+}
+
+// composite draws clr over row y of the image using the coverage
+// accumulated for that row.
+func (c *rasterCanvas) composite(y int, clr color.Color) {
+	r, g, b, a := clr.RGBA()
+	bounds := c.img.Bounds()
+	for x := 0; x < bounds.Dx(); x++ {
+		cov := math.Min(c.cover[x], 1)
+		if cov <= 0 {
+			continue
+		}
+
+		i := c.img.PixOffset(bounds.Min.X+x, y)
+		pix := c.img.Pix[i : i+4 : i+4]
+		sa := float64(a) / 0xffff * cov
+		pix[0] = blend(pix[0], float64(r)/0xffff*cov, sa)
+		pix[1] = blend(pix[1], float64(g)/0xffff*cov, sa)
+		pix[2] = blend(pix[2], float64(b)/0xffff*cov, sa)
+		pix[3] = blend(pix[3], sa, sa)
+	}
+}
+
+// blend returns the premultiplied channel dst after compositing the
+// premultiplied channel src of alpha sa over it.
+func blend(dst uint8, src, sa float64) uint8 {
+	v := src*0xff + float64(dst)*(1-sa)
+	return uint8(math.Min(math.Round(v), 0xff))
+}
+
+// strokePieces returns closed paths which together cover a line of the
+// provided width through pts using round joins and caps. The paths share
+// the same orientation so they can be filled together using the non-zero
+// winding rule.
+func strokePieces(pts []Point, width float64) [][]Point {
+	half := width / 2
+	var pieces [][]Point
+	for i := range pts {
+		pieces = append(pieces, circlePath(pts[i], half))
+		if i == 0 {
+			continue
+		}
+
+		a, b := pts[i-1], pts[i]
+		dx, dy := b.X-a.X, b.Y-a.Y
+		length := math.Hypot(dx, dy)
+		if length == 0 {
+			continue
+		}
+
+		nx, ny := -dy/length*half, dx/length*half
+		pieces = append(pieces, []Point{
+			{X: a.X + nx, Y: a.Y + ny},
+			{X: a.X - nx, Y: a.Y - ny},
+			{X: b.X - nx, Y: b.Y - ny},
+			{X: b.X + nx, Y: b.Y + ny},
+		})
+	}
+
+	return pieces
+}
+
+// circlePath returns a closed path approximating a circle of the provided
+// radius around c, given in pixel space.
+func circlePath(c Point, radius float64) []Point {
+	n := int(math.Ceil(math.Pi * radius))
+	if n < 8 {
+		n = 8
+	}
+	if n > 64 {
+		n = 64
+	}
+
+	path := make([]Point, n)
+	for i := range path {
+		theta := 2 * math.Pi * float64(i) / float64(n)
+		path[i] = Point{X: c.X + radius*math.Cos(theta), Y: c.Y + radius*math.Sin(theta)}
+	}
+
+	return path
+}
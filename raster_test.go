@@ -0,0 +1,174 @@
+package mfcg
+
+import (
+	"bytes"
+	"errors"
+	"image/color"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMap_RasterizeScale(t *testing.T) {
+	red := color.RGBA{R: 0xff, A: 0xff}
+	blue := color.RGBA{B: 0xff, A: 0xff}
+	style := &Style{
+		Background: color.White,
+		Buildings:  LayerStyle{Fill: red},
+		Roads:      LayerStyle{Stroke: blue},
+	}
+	mp := &Map{
+		Earth: Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}}},
+		Buildings: []Polygon{
+			{Coords: [][]Point{
+				{{X: 1, Y: 1}, {X: 5, Y: 1}, {X: 5, Y: 5}, {X: 1, Y: 5}},
+				{{X: 2, Y: 2}, {X: 3, Y: 2}, {X: 3, Y: 3}, {X: 2, Y: 3}},
+			}},
+			{Coords: [][]Point{{{X: 6.5, Y: 1}, {X: 8, Y: 1}, {X: 8, Y: 2}, {X: 6.5, Y: 2}}}},
+		},
+		Roads: []LineString{{Width: 2, Coords: []Point{{X: 0, Y: 8}, {X: 10, Y: 8}}}},
+	}
+
+	// The image spans the map grown by half the road's width, so map Point
+	// (x, y) is drawn at pixel (x+1, y+1).
+	img := mp.RasterizeScale(style, 1)
+	if diff := cmp.Diff(img.Bounds().Size().X, 12); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	tests := []struct {
+		name string
+		x, y int
+		want color.RGBA
+	}{
+		{name: "Background", x: 10, y: 5, want: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}},
+		{name: "Building interior", x: 2, y: 2, want: red},
+		{name: "Building hole", x: 3, y: 3, want: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}},
+		{name: "Half covered pixel", x: 7, y: 2, want: color.RGBA{R: 0xff, G: 0x80, B: 0x80, A: 0xff}},
+		{name: "Road", x: 5, y: 8, want: blue},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := img.RGBAAt(test.x, test.y)
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMap_Rasterize(t *testing.T) {
+	mp := &Map{
+		Earth: Polygon{Coords: [][]Point{{{X: -20, Y: -5}, {X: 20, Y: -5}, {X: 20, Y: 5}, {X: -20, Y: 5}}}},
+	}
+	style := &Style{Earth: LayerStyle{Fill: color.Black}}
+
+	img := mp.Rasterize(style, 80, 80)
+	if diff := cmp.Diff(img.Bounds().Size().Y, 80); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	// The map is twice as wide as the image is tall, so it is centered
+	// vertically between two 30 pixel margins.
+	if got := img.RGBAAt(40, 29); got.A != 0 {
+		t.Errorf("got: <%v>, want transparent margin", got)
+	}
+	if got := img.RGBAAt(40, 30); got.A != 0xff {
+		t.Errorf("got: <%v>, want opaque earth", got)
+	}
+}
+
+func TestMap_WritePNG(t *testing.T) {
+	mp := &Map{
+		Buildings: []Polygon{{Coords: [][]Point{{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 2}}}}},
+	}
+
+	var buf bytes.Buffer
+	if err := mp.WritePNG(&buf, nil, 32, 16); err != nil {
+		t.Fatalf("got: <%v>, want error: <%v>", err, false)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(img.Bounds().Size().X, 32); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestMap_WritePNG_size(t *testing.T) {
+	mp := &Map{
+		Buildings: []Polygon{{Coords: [][]Point{{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 2}}}}},
+	}
+
+	tests := []struct {
+		name          string
+		width, height int
+		wantErr       error
+	}{
+		{name: "Positive size", width: 4, height: 2, wantErr: nil},
+		{name: "Zero width", width: 0, height: 2, wantErr: ErrImageSize},
+		{name: "Negative width", width: -4, height: 2, wantErr: ErrImageSize},
+		{name: "Negative height", width: 4, height: -2, wantErr: ErrImageSize},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := mp.WritePNG(&buf, nil, test.width, test.height)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("got: <%v>, want error: <%v>", err, test.wantErr)
+			}
+		})
+	}
+
+	// Rasterizing at a negative size yields an empty image.
+	if got := mp.Rasterize(nil, -4, -2).Bounds(); !got.Empty() {
+		t.Errorf("got: <%v>, want empty image", got)
+	}
+	if got := mp.RasterizeScale(nil, -1).Bounds(); !got.Empty() {
+		t.Errorf("got: <%v>, want empty image", got)
+	}
+}
+
+func Test_addSpan(t *testing.T) {
+	tests := []struct {
+		name   string
+		x0, x1 float64
+		want   []float64
+	}{
+		{name: "Within one pixel", x0: 1.25, x1: 1.75, want: []float64{0, 0.5, 0, 0}},
+		{name: "Across pixels", x0: 0.5, x1: 2.25, want: []float64{0.5, 1, 0.25, 0}},
+		{name: "Clipped", x0: -3, x1: 9, want: []float64{1, 1, 1, 1}},
+		{name: "Outside", x0: 5, x1: 9, want: []float64{0, 0, 0, 0}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := make([]float64, 4)
+			addSpan(got, test.x0, test.x1, 1)
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_strokePieces(t *testing.T) {
+	pts := []Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: -10}, {X: 0, Y: 5}}
+	for i, piece := range strokePieces(pts, 2) {
+		area := 0.0
+		for j := range piece {
+			a, b := piece[j], piece[(j+1)%len(piece)]
+			area += a.X*b.Y - b.X*a.Y
+		}
+		if area <= 0 || math.IsNaN(area) {
+			t.Errorf("piece %d: got area <%v>, want positive area", i, area/2)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package mfcg
+
+import "math"
+
+// Rect is an axis-aligned rectangle given by its minimum and maximum
+// corners.
+type Rect struct {
+	Min Point
+	Max Point
+}
+
+// emptyRect returns a Rect containing no Points. Extending it by any Point
+// results in a Rect containing only that Point.
+func emptyRect() Rect {
+	return Rect{
+		Min: Point{X: math.Inf(1), Y: math.Inf(1)},
+		Max: Point{X: math.Inf(-1), Y: math.Inf(-1)},
+	}
+}
+
+// Empty reports whether the Rect contains no Points. The bounds of a
+// geometry without any Points are empty.
+func (r Rect) Empty() bool {
+	return r.Min.X > r.Max.X || r.Min.Y > r.Max.Y
+}
+
+// Dx returns the width of the Rect.
+func (r Rect) Dx() float64 {
+	if r.Empty() {
+		return 0
+	}
+	return r.Max.X - r.Min.X
+}
+
+// Dy returns the height of the Rect.
+func (r Rect) Dy() float64 {
+	if r.Empty() {
+		return 0
+	}
+	return r.Max.Y - r.Min.Y
+}
+
+// Center returns the center of the Rect.
+func (r Rect) Center() Point {
+	return Point{X: (r.Min.X + r.Max.X) / 2, Y: (r.Min.Y + r.Max.Y) / 2}
+}
+
+// Union returns the smallest Rect containing both r and s.
+func (r Rect) Union(s Rect) Rect {
+	return Rect{
+		Min: Point{X: math.Min(r.Min.X, s.Min.X), Y: math.Min(r.Min.Y, s.Min.Y)},
+		Max: Point{X: math.Max(r.Max.X, s.Max.X), Y: math.Max(r.Max.Y, s.Max.Y)},
+	}
+}
+
+// Inset returns the Rect shrunk by n on every side. A negative n grows the
+// Rect instead. An empty Rect remains empty.
+func (r Rect) Inset(n float64) Rect {
+	if r.Empty() {
+		return r
+	}
+
+	return Rect{
+		Min: Point{X: r.Min.X + n, Y: r.Min.Y + n},
+		Max: Point{X: r.Max.X - n, Y: r.Max.Y - n},
+	}
+}
+
+// extend returns the smallest Rect containing r and each of the provided
+// Points.
+func (r Rect) extend(pts []Point) Rect {
+	for _, pt := range pts {
+		r.Min.X, r.Min.Y = math.Min(r.Min.X, pt.X), math.Min(r.Min.Y, pt.Y)
+		r.Max.X, r.Max.Y = math.Max(r.Max.X, pt.X), math.Max(r.Max.Y, pt.Y)
+	}
+
+	return r
+}
+
+// Bounds returns the smallest Rect containing every feature of the Map.
+// Districts only overlay the features and are left out.
+func (m *Map) Bounds() Rect {
+	r := emptyRect()
+	for _, l := range m.polygonLayers() {
+		for _, p := range l.polys {
+			r = r.Union(p.Bounds())
+		}
+	}
+	for _, l := range m.lineLayers() {
+		for _, ln := range l.lines {
+			r = r.Union(ln.Bounds())
+		}
+	}
+
+	return r.extend(m.Trees)
+}
+
+// Intersects reports whether r and s share at least one Point.
+func (r Rect) Intersects(s Rect) bool {
+	return r.Min.X <= s.Max.X && s.Min.X <= r.Max.X && r.Min.Y <= s.Max.Y && s.Min.Y <= r.Max.Y
+}
+
+// Contains reports whether Point pt lies within the Rect, including its
+// edges.
+func (r Rect) Contains(pt Point) bool {
+	return r.Min.X <= pt.X && pt.X <= r.Max.X && r.Min.Y <= pt.Y && pt.Y <= r.Max.Y
+}
+
+// Distance returns the euclidean distance between Point pt and the Rect.
+// The distance is zero if the Rect contains pt.
+func (r Rect) Distance(pt Point) float64 {
+	dx := math.Max(0, math.Max(r.Min.X-pt.X, pt.X-r.Max.X))
+	dy := math.Max(0, math.Max(r.Min.Y-pt.Y, pt.Y-r.Max.Y))
+
+	return math.Hypot(dx, dy)
+}
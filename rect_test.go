@@ -0,0 +1,94 @@
+package mfcg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMap_Bounds(t *testing.T) {
+	tests := []struct {
+		name string
+		mp   *Map
+		want Rect
+	}{
+		{
+			name: "Test data map",
+			mp:   testMap(t, testFileMap),
+			want: Rect{Min: Point{X: -435.589, Y: -128.437}, Max: Point{X: 201.401, Y: 87.117}},
+		},
+		{
+			name: "Single layer",
+			mp:   &Map{Roads: []LineString{{Coords: []Point{{X: 1, Y: 2}, {X: -3, Y: 4}}}}},
+			want: Rect{Min: Point{X: -3, Y: 2}, Max: Point{X: 1, Y: 4}},
+		},
+		{
+			name: "Trees",
+			mp:   testMap(t, testFileTrees),
+			want: Rect{Min: Point{X: -40.415, Y: 58.844}, Max: Point{X: -22.108, Y: 81.552}},
+		},
+		{
+			name: "Trees only",
+			mp:   &Map{Trees: []Point{{X: 1, Y: -2}, {X: -3, Y: 4}}},
+			want: Rect{Min: Point{X: -3, Y: -2}, Max: Point{X: 1, Y: 4}},
+		},
+		{
+			name: "Districts only",
+			mp:   &Map{Districts: []District{{Name: "Docks", Polygon: Polygon{Coords: [][]Point{{{X: 1, Y: 2}, {X: 3, Y: 4}, {X: 1, Y: 4}}}}}}},
+			want: emptyRect(),
+		},
+		{
+			name: "Empty map",
+			mp:   &Map{},
+			want: emptyRect(),
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := test.mp.Bounds()
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRect(t *testing.T) {
+	r := Rect{Min: Point{X: -1, Y: 2}, Max: Point{X: 3, Y: 8}}
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{name: "Dx", got: r.Dx(), want: 4.0},
+		{name: "Dy", got: r.Dy(), want: 6.0},
+		{name: "Center", got: r.Center(), want: Point{X: 1, Y: 5}},
+		{name: "Empty", got: r.Empty(), want: false},
+		{name: "Empty Dx", got: emptyRect().Dx(), want: 0.0},
+		{name: "Empty rect", got: emptyRect().Empty(), want: true},
+		{name: "Inset", got: r.Inset(1), want: Rect{Min: Point{X: 0, Y: 3}, Max: Point{X: 2, Y: 7}}},
+		{name: "Inset empty", got: emptyRect().Inset(-1).Empty(), want: true},
+		{
+			name: "Union",
+			got:  r.Union(Rect{Min: Point{X: 0, Y: -4}, Max: Point{X: 5, Y: 5}}),
+			want: Rect{Min: Point{X: -1, Y: -4}, Max: Point{X: 5, Y: 8}},
+		},
+		{name: "Union empty", got: r.Union(emptyRect()), want: r},
+		{name: "Intersects", got: r.Intersects(Rect{Min: Point{X: 3, Y: 8}, Max: Point{X: 4, Y: 9}}), want: true},
+		{name: "Intersects disjoint", got: r.Intersects(Rect{Min: Point{X: 4, Y: 0}, Max: Point{X: 5, Y: 9}}), want: false},
+		{name: "Intersects empty", got: r.Intersects(emptyRect()), want: false},
+		{name: "Contains", got: r.Contains(Point{X: -1, Y: 5}), want: true},
+		{name: "Contains outside", got: r.Contains(Point{X: -2, Y: 5}), want: false},
+		{name: "Distance inside", got: r.Distance(Point{X: 0, Y: 3}), want: 0.0},
+		{name: "Distance outside", got: r.Distance(Point{X: 6, Y: -2}), want: 5.0},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if diff := cmp.Diff(test.got, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package mfcg
+
+// RepairOptions configures how Repair normalizes a Map.
+type RepairOptions struct {
+	Closed    bool    // Closed repeats the first Point of every ring at its end.
+	Tolerance float64 // Tolerance is the distance within which building and wall Points are snapped together. Zero disables snapping.
+}
+
+// RepairSummary counts the changes made by Repair.
+type RepairSummary struct {
+	Snapped    int // Snapped is the number of Points moved onto a nearby Point.
+	Duplicates int // Duplicates is the number of repeated consecutive Points removed.
+	Collinear  int // Collinear is the number of Points removed from straight edges.
+	Dropped    int // Dropped is the number of degenerate Polygons, holes and lines removed.
+	Reoriented int // Reoriented is the number of rings whose Points were reversed.
+	Reclosed   int // Reclosed is the number of rings which were closed or opened.
+}
+
+// Repair normalizes the geometry of the Map in place and returns a summary
+// of the changes. Building and wall Points within the provided tolerance of
+// each other are first snapped together. Every ring then loses its repeated
+// and collinear Points and is dropped if fewer than 3 Points remain, taking
+// its Polygon with it if it is an exterior. Exteriors are wound
+// counterclockwise and holes clockwise on a y-up plane, following the right
+// hand rule of RFC 7946, and rings are closed or left open as requested.
+// Lines lose their repeated Points and are dropped if fewer than 2 remain,
+// and so do the paths of open walls.
+func (m *Map) Repair(opts RepairOptions) RepairSummary {
+	var sum RepairSummary
+	if opts.Tolerance > 0 {
+		sum.Snapped = snapPolygons(opts.Tolerance, m.Buildings, m.Walls)
+	}
+
+	if m.Earth.Coords != nil {
+		if p, ok := repairPolygon(m.Earth, opts, &sum); ok {
+			m.Earth = p
+		} else {
+			m.Earth = Polygon{}
+		}
+	}
+
+	for _, polys := range []*[]Polygon{&m.Buildings, &m.Fields, &m.Greens, &m.Prisms, &m.Squares, &m.Walls, &m.Water} {
+		if polys == &m.Walls && m.OpenWalls {
+			m.Walls = repairPaths(m.Walls, &sum)
+			continue
+		}
+		*polys = repairPolygons(*polys, opts, &sum)
+	}
+
+	if m.Districts != nil {
+		dists := m.Districts[:0]
+		for _, d := range m.Districts {
+			if p, ok := repairPolygon(d.Polygon, opts, &sum); ok {
+				d.Polygon = p
+				dists = append(dists, d)
+			}
+		}
+		m.Districts = dists
+	}
+
+	for _, lines := range []*[]LineString{&m.Planks, &m.Rivers, &m.Roads} {
+		*lines = repairLineStrings(*lines, &sum)
+	}
+
+	return sum
+}
+
+// snapPolygons moves every Point of the provided Polygons within tolerance
+// of a previously seen Point onto that Point and returns the number of Points
+// moved.
+func snapPolygons(tolerance float64, layers ...[]Polygon) int {
+	s := newSnapper(tolerance)
+	var seen []Point
+	moved := 0
+
+	for _, polys := range layers {
+		for _, p := range polys {
+			for _, ring := range p.Coords {
+				for i, pt := range ring {
+					n, added := s.snap(pt, len(seen))
+					if added {
+						seen = append(seen, pt)
+						continue
+					}
+					if seen[n] != pt {
+						ring[i] = seen[n]
+						moved++
+					}
+				}
+			}
+		}
+	}
+
+	return moved
+}
+
+// repairPolygons returns the provided Polygons repaired, without the
+// degenerate ones. A nil slice is returned as is.
+func repairPolygons(polys []Polygon, opts RepairOptions, sum *RepairSummary) []Polygon {
+	if polys == nil {
+		return nil
+	}
+
+	result := polys[:0]
+	for _, p := range polys {
+		if p, ok := repairPolygon(p, opts, sum); ok {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+// repairPaths returns the provided Polygons whose rings are open paths
+// repaired. Each path is repaired like a line, and a Polygon losing every
+// path is dropped.
+func repairPaths(polys []Polygon, sum *RepairSummary) []Polygon {
+	if polys == nil {
+		return nil
+	}
+
+	result := polys[:0]
+	for _, p := range polys {
+		paths := make([][]Point, 0, len(p.Coords))
+		for _, ring := range p.Coords {
+			pts, n := removeDuplicates(append([]Point(nil), ring...))
+			sum.Duplicates += n
+			if len(pts) < 2 {
+				sum.Dropped++
+				continue
+			}
+			paths = append(paths, pts)
+		}
+		if len(paths) == 0 {
+			continue
+		}
+
+		p.Coords = paths
+		result = append(result, p)
+	}
+
+	return result
+}
+
+// repairPolygon returns Polygon p repaired. If p's exterior is degenerate,
+// false is returned and the Polygon should be dropped.
+func repairPolygon(p Polygon, opts RepairOptions, sum *RepairSummary) (Polygon, bool) {
+	rings := make([][]Point, 0, len(p.Coords))
+	for i, ring := range p.Coords {
+		fixed, ok := repairRing(ring, i == 0, opts, sum)
+		if !ok {
+			sum.Dropped++
+			if i == 0 {
+				return p, false
+			}
+			continue
+		}
+		rings = append(rings, fixed)
+	}
+
+	if len(rings) == 0 {
+		sum.Dropped++
+		return p, false
+	}
+	p.Coords = rings
+
+	return p, true
+}
+
+// repairRing returns a repaired copy of the provided ring, wound as an
+// exterior if outer is true and as a hole otherwise. If fewer than 3 Points
+// remain, false is returned and the ring should be dropped.
+func repairRing(ring []Point, outer bool, opts RepairOptions, sum *RepairSummary) ([]Point, bool) {
+	wasClosed := isClosed(ring)
+	pts := append([]Point(nil), openRing(ring)...)
+
+	pts, n := removeDuplicates(pts)
+	for len(pts) > 1 && pts[len(pts)-1] == pts[0] {
+		pts = pts[:len(pts)-1]
+		n++
+	}
+	sum.Duplicates += n
+
+	pts, n = removeCollinear(pts)
+	sum.Collinear += n
+	if len(pts) < 3 {
+		return nil, false
+	}
+
+	if a := signedArea(pts); (a < 0) == outer {
+		for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+			pts[i], pts[j] = pts[j], pts[i]
+		}
+		sum.Reoriented++
+	}
+
+	if opts.Closed {
+		pts = append(pts, pts[0])
+	}
+	if wasClosed != opts.Closed {
+		sum.Reclosed++
+	}
+
+	return pts, true
+}
+
+// removeDuplicates removes repeated consecutive Points from pts in place and
+// returns the result along with the number of Points removed.
+func removeDuplicates(pts []Point) ([]Point, int) {
+	if len(pts) == 0 {
+		return pts, 0
+	}
+
+	result := pts[:1]
+	for _, pt := range pts[1:] {
+		if pt != result[len(result)-1] {
+			result = append(result, pt)
+		}
+	}
+
+	return result, len(pts) - len(result)
+}
+
+// removeCollinear removes every Point of the provided open ring lying on the
+// segment between its neighbors, in place, and returns the result along with
+// the number of Points removed.
+func removeCollinear(ring []Point) ([]Point, int) {
+	removed := 0
+	for changed := true; changed; {
+		changed = false
+		for i := 0; i < len(ring) && len(ring) >= 3; i++ {
+			prev, next := ring[(i+len(ring)-1)%len(ring)], ring[(i+1)%len(ring)]
+			if segmentDistance(ring[i], prev, next) <= epsilon {
+				ring = append(ring[:i], ring[i+1:]...)
+				removed++
+				changed = true
+				i--
+			}
+		}
+	}
+
+	return ring, removed
+}
+
+// repairLineStrings returns the provided LineStrings without repeated
+// consecutive Points, dropping those left with fewer than 2 Points. A nil
+// slice is returned as is.
+func repairLineStrings(lines []LineString, sum *RepairSummary) []LineString {
+	if lines == nil {
+		return nil
+	}
+
+	result := lines[:0]
+	for _, ln := range lines {
+		pts, n := removeDuplicates(append([]Point(nil), ln.Coords...))
+		sum.Duplicates += n
+		if len(pts) < 2 {
+			sum.Dropped++
+			continue
+		}
+
+		ln.Coords = pts
+		result = append(result, ln)
+	}
+
+	return result
+}
@@ -0,0 +1,135 @@
+package mfcg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMap_Repair(t *testing.T) {
+	tests := []struct {
+		name    string
+		mp      *Map
+		opts    RepairOptions
+		want    *Map
+		wantSum RepairSummary
+	}{
+		{
+			name: "Reoriented rings",
+			mp: &Map{Squares: []Polygon{{Coords: [][]Point{
+				{{X: 0, Y: 0}, {X: 0, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 0}},
+				{{X: 2, Y: 2}, {X: 4, Y: 2}, {X: 4, Y: 4}, {X: 2, Y: 4}},
+			}}}},
+			want: &Map{Squares: []Polygon{{Coords: [][]Point{
+				{{X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 0}},
+				{{X: 2, Y: 4}, {X: 4, Y: 4}, {X: 4, Y: 2}, {X: 2, Y: 2}},
+			}}}},
+			wantSum: RepairSummary{Reoriented: 2},
+		},
+		{
+			name: "Closed rings",
+			mp:   &Map{Earth: Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}}}},
+			opts: RepairOptions{Closed: true},
+			want: &Map{Earth: Polygon{Coords: [][]Point{
+				{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 0}},
+			}}},
+			wantSum: RepairSummary{Reclosed: 1},
+		},
+		{
+			name: "Open walls",
+			mp: &Map{
+				Walls: []Polygon{
+					{Width: 5, Coords: [][]Point{{{X: 0, Y: 0}, {X: 0, Y: 0}, {X: 10, Y: 0}}, {{X: 5, Y: 5}}}},
+					{Width: 5, Coords: [][]Point{{{X: 1, Y: 1}}}},
+				},
+				OpenWalls: true,
+			},
+			opts: RepairOptions{Closed: true},
+			want: &Map{
+				Walls:     []Polygon{{Width: 5, Coords: [][]Point{{{X: 0, Y: 0}, {X: 10, Y: 0}}}}},
+				OpenWalls: true,
+			},
+			wantSum: RepairSummary{Duplicates: 1, Dropped: 2},
+		},
+		{
+			name: "Opened rings",
+			mp: &Map{Water: []Polygon{{Coords: [][]Point{
+				{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 0}},
+			}}}},
+			want:    &Map{Water: []Polygon{{Coords: [][]Point{{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}}}}},
+			wantSum: RepairSummary{Reclosed: 1},
+		},
+		{
+			name: "Duplicate and collinear points",
+			mp: &Map{Fields: []Polygon{{Coords: [][]Point{
+				{{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 0}, {X: 0, Y: 0}},
+			}}}},
+			opts: RepairOptions{Closed: true},
+			want: &Map{Fields: []Polygon{{Coords: [][]Point{
+				{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 0}},
+			}}}},
+			wantSum: RepairSummary{Duplicates: 2, Collinear: 1},
+		},
+		{
+			name: "Degenerate polygons",
+			mp: &Map{
+				Buildings: []Polygon{
+					{Coords: [][]Point{{{X: 0, Y: 0}, {X: 5, Y: 5}, {X: 10, Y: 10}}}},
+					{Coords: [][]Point{{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}, {{X: 1, Y: 1}, {X: 2, Y: 2}}}},
+				},
+				Districts: []District{{Name: "Docks", Polygon: Polygon{Coords: [][]Point{{{X: 1, Y: 1}, {X: 1, Y: 1}}}}}},
+			},
+			want: &Map{
+				Buildings: []Polygon{{Coords: [][]Point{{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}}}},
+				Districts: []District{},
+			},
+			wantSum: RepairSummary{Collinear: 1, Dropped: 3},
+		},
+		{
+			name: "Lines",
+			mp: &Map{Roads: []LineString{
+				{Width: 8, Coords: []Point{{X: 0, Y: 0}, {X: 0, Y: 0}, {X: 5, Y: 5}}},
+				{Width: 8, Coords: []Point{{X: 1, Y: 1}, {X: 1, Y: 1}}},
+			}},
+			want: &Map{Roads: []LineString{
+				{Width: 8, Coords: []Point{{X: 0, Y: 0}, {X: 5, Y: 5}}},
+			}},
+			wantSum: RepairSummary{Duplicates: 2, Dropped: 1},
+		},
+		{
+			name: "Snapped buildings and walls",
+			mp: &Map{
+				Buildings: []Polygon{{Coords: [][]Point{{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}}}},
+				Walls:     []Polygon{{Width: 2, Coords: [][]Point{{{X: 10.05, Y: -0.05}, {X: 20, Y: 0}, {X: 20, Y: 10}}}}},
+				Water:     []Polygon{{Coords: [][]Point{{{X: 0.01, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}}}},
+			},
+			opts: RepairOptions{Tolerance: 0.1},
+			want: &Map{
+				Buildings: []Polygon{{Coords: [][]Point{{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}}}},
+				Walls:     []Polygon{{Width: 2, Coords: [][]Point{{{X: 10, Y: 0}, {X: 20, Y: 0}, {X: 20, Y: 10}}}}},
+				Water:     []Polygon{{Coords: [][]Point{{{X: 0.01, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}}}},
+			},
+			wantSum: RepairSummary{Snapped: 1},
+		},
+		{
+			name:    "Fixture",
+			mp:      testMap(t, testFileTrees),
+			want:    testMap(t, testFileTrees),
+			wantSum: RepairSummary{},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			sum := test.mp.Repair(test.opts)
+			if diff := cmp.Diff(sum, test.wantSum); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(test.mp, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
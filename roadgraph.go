@@ -0,0 +1,299 @@
+package mfcg
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+	"sort"
+)
+
+var (
+	// ErrNoPath is returned when two nodes of a RoadGraph are not connected.
+	ErrNoPath = errors.New("no path between road graph nodes")
+	// ErrNodeRange is returned when a node index is not part of a RoadGraph.
+	ErrNodeRange = errors.New("road graph node index out of range")
+)
+
+// RoadGraph is the network formed by a Map's roads. Road vertices lying
+// within a tolerance of one another are merged into a single node, so roads
+// meeting at a junction share that junction's node.
+type RoadGraph struct {
+	Nodes []Point
+	Edges []RoadEdge
+	adj   [][]int
+}
+
+// RoadEdge connects two nodes of a RoadGraph along a single road segment.
+type RoadEdge struct {
+	From   int     // From is the index of the node the segment starts at.
+	To     int     // To is the index of the node the segment ends at.
+	Length float64 // Length is the distance between both nodes.
+	Width  float64 // Width is the width of the road the segment belongs to.
+	Road   int     // Road is the index of the segment's road in Map.Roads.
+}
+
+// NewRoadGraph returns the RoadGraph formed by the roads of the provided
+// Map. Road vertices closer than tolerance to an existing node are merged
+// into that node. A negative tolerance is treated as zero, so only identical
+// vertices are merged.
+func NewRoadGraph(m *Map, tolerance float64) *RoadGraph {
+	g := &RoadGraph{}
+	s := newSnapper(tolerance)
+
+	for r, ln := range m.Roads {
+		prev := -1
+		for _, pt := range ln.Coords {
+			n, added := s.snap(pt, len(g.Nodes))
+			if added {
+				g.Nodes = append(g.Nodes, pt)
+				g.adj = append(g.adj, nil)
+			}
+
+			if prev >= 0 && prev != n {
+				g.addEdge(RoadEdge{
+					From:   prev,
+					To:     n,
+					Length: distance(g.Nodes[prev], g.Nodes[n]),
+					Width:  ln.Width,
+					Road:   r,
+				})
+			}
+			prev = n
+		}
+	}
+
+	return g
+}
+
+// addEdge adds the provided edge to the graph.
+func (g *RoadGraph) addEdge(e RoadEdge) {
+	g.Edges = append(g.Edges, e)
+	g.adj[e.From] = append(g.adj[e.From], len(g.Edges)-1)
+	g.adj[e.To] = append(g.adj[e.To], len(g.Edges)-1)
+}
+
+// Degree returns the number of distinct nodes sharing an edge with node n.
+// Parallel edges, such as two roads running between the same nodes, count
+// once.
+func (g *RoadGraph) Degree(n int) int {
+	return len(g.Neighbors(n))
+}
+
+// Neighbors returns the distinct nodes sharing an edge with node n.
+func (g *RoadGraph) Neighbors(n int) []int {
+	var nbrs []int
+	seen := make(map[int]bool)
+	for _, e := range g.adj[n] {
+		if nbr := g.other(e, n); !seen[nbr] {
+			seen[nbr] = true
+			nbrs = append(nbrs, nbr)
+		}
+	}
+
+	return nbrs
+}
+
+// other returns the node at the opposite end of edge e from node n.
+func (g *RoadGraph) other(e, n int) int {
+	if g.Edges[e].From == n {
+		return g.Edges[e].To
+	}
+	return g.Edges[e].From
+}
+
+// Nearest returns the node closest to the provided Point, or -1 if the graph
+// has no nodes.
+func (g *RoadGraph) Nearest(pt Point) int {
+	best, bestDist := -1, math.Inf(1)
+	for i, n := range g.Nodes {
+		if d := distance(n, pt); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+
+	return best
+}
+
+// DeadEnds returns the nodes connected to a single other node.
+func (g *RoadGraph) DeadEnds() []int {
+	var ends []int
+	for n := range g.Nodes {
+		if g.Degree(n) == 1 {
+			ends = append(ends, n)
+		}
+	}
+
+	return ends
+}
+
+// Junctions returns the nodes connected to three or more other nodes.
+func (g *RoadGraph) Junctions() []int {
+	var juncs []int
+	for n := range g.Nodes {
+		if g.Degree(n) >= 3 {
+			juncs = append(juncs, n)
+		}
+	}
+
+	return juncs
+}
+
+// Components returns the connected components of the graph. Each component
+// lists its nodes in ascending order.
+func (g *RoadGraph) Components() [][]int {
+	seen := make([]bool, len(g.Nodes))
+	var comps [][]int
+	for start := range g.Nodes {
+		if seen[start] {
+			continue
+		}
+
+		seen[start] = true
+		comp := []int{start}
+		for i := 0; i < len(comp); i++ {
+			for _, nbr := range g.Neighbors(comp[i]) {
+				if !seen[nbr] {
+					seen[nbr] = true
+					comp = append(comp, nbr)
+				}
+			}
+		}
+
+		sort.Ints(comp)
+		comps = append(comps, comp)
+	}
+
+	return comps
+}
+
+// ShortestPath returns the nodes along the shortest path from node from to
+// node to, both included, and the length of that path. The path is found
+// using A* search with the straight-line distance as heuristic.
+func (g *RoadGraph) ShortestPath(from, to int) ([]int, float64, error) {
+	if from < 0 || from >= len(g.Nodes) || to < 0 || to >= len(g.Nodes) {
+		return nil, 0, ErrNodeRange
+	}
+
+	dist := make([]float64, len(g.Nodes))
+	prev := make([]int, len(g.Nodes))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	dist[from] = 0
+
+	open := &nodeQueue{{node: from, priority: distance(g.Nodes[from], g.Nodes[to])}}
+	for open.Len() > 0 {
+		cur := heap.Pop(open).(nodeItem)
+		if cur.node == to {
+			break
+		}
+		if cur.priority-distance(g.Nodes[cur.node], g.Nodes[to]) > dist[cur.node] {
+			continue
+		}
+
+		for _, e := range g.adj[cur.node] {
+			nbr := g.other(e, cur.node)
+			d := dist[cur.node] + g.Edges[e].Length
+			if d < dist[nbr] {
+				dist[nbr] = d
+				prev[nbr] = cur.node
+				heap.Push(open, nodeItem{node: nbr, priority: d + distance(g.Nodes[nbr], g.Nodes[to])})
+			}
+		}
+	}
+
+	if math.IsInf(dist[to], 1) {
+		return nil, 0, ErrNoPath
+	}
+
+	var path []int
+	for n := to; n != -1; n = prev[n] {
+		path = append(path, n)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, dist[to], nil
+}
+
+// nodeItem is a node awaiting a visit during a search, ordered by priority.
+type nodeItem struct {
+	node     int
+	priority float64
+}
+
+// nodeQueue is a min-heap of nodeItems implementing heap.Interface.
+type nodeQueue []nodeItem
+
+func (q nodeQueue) Len() int            { return len(q) }
+func (q nodeQueue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q nodeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x interface{}) { *q = append(*q, x.(nodeItem)) }
+func (q *nodeQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// snapper merges Points lying within a tolerance of one another. Points are
+// bucketed into a grid of cells as wide as the tolerance, so only the
+// neighboring cells of a Point need to be searched.
+type snapper struct {
+	tolerance float64
+	cells     map[[2]int64][]snapped
+}
+
+// snapped is a Point known to a snapper along with its index.
+type snapped struct {
+	pt    Point
+	index int
+}
+
+// newSnapper returns a snapper merging Points within the provided
+// tolerance. A tolerance of zero or less only merges identical Points.
+func newSnapper(tolerance float64) *snapper {
+	if tolerance < 0 {
+		tolerance = 0
+	}
+
+	return &snapper{
+		tolerance: tolerance,
+		cells:     make(map[[2]int64][]snapped),
+	}
+}
+
+// snap returns the index of the known Point within tolerance of pt. If no
+// such Point exists, pt is recorded using the provided index, which is
+// returned along with true.
+func (s *snapper) snap(pt Point, index int) (int, bool) {
+	cell := s.cell(pt)
+	best, bestDist := -1, math.Inf(1)
+	for dx := int64(-1); dx <= 1; dx++ {
+		for dy := int64(-1); dy <= 1; dy++ {
+			for _, c := range s.cells[[2]int64{cell[0] + dx, cell[1] + dy}] {
+				if d := distance(c.pt, pt); d <= s.tolerance && d < bestDist {
+					best, bestDist = c.index, d
+				}
+			}
+		}
+	}
+	if best >= 0 {
+		return best, false
+	}
+
+	s.cells[cell] = append(s.cells[cell], snapped{pt: pt, index: index})
+	return index, true
+}
+
+// cell returns the grid cell containing pt.
+func (s *snapper) cell(pt Point) [2]int64 {
+	size := s.tolerance
+	if size <= 0 {
+		size = 1
+	}
+
+	return [2]int64{int64(math.Floor(pt.X / size)), int64(math.Floor(pt.Y / size))}
+}
@@ -0,0 +1,207 @@
+package mfcg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// testRoadMap returns a Map whose roads form a square loop with a spur and a
+// separate road nearly touching the loop:
+//
+//	(0,0)---(10,0)---(20,0)
+//	  |        |
+//	(0,8)---(10,10)        (30,30)---(40,30)
+func testRoadMap() *Map {
+	return &Map{
+		Roads: []LineString{
+			{Width: 8, Coords: []Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 20, Y: 0}}},
+			{Width: 4, Coords: []Point{{X: 10.1, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 8}, {X: 0, Y: 0.05}}},
+			{Width: 4, Coords: []Point{{X: 30, Y: 30}, {X: 40, Y: 30}}},
+		},
+	}
+}
+
+func TestNewRoadGraph(t *testing.T) {
+	tests := []struct {
+		name      string
+		mp        *Map
+		tolerance float64
+		wantNodes int
+		wantEdges int
+	}{
+		{
+			name:      "Snapped junctions",
+			mp:        testRoadMap(),
+			tolerance: 0.5,
+			wantNodes: 7,
+			wantEdges: 6,
+		},
+		{
+			name:      "Exact junctions",
+			mp:        testRoadMap(),
+			tolerance: 0,
+			wantNodes: 9,
+			wantEdges: 6,
+		},
+		{
+			name: "Negative tolerance",
+			mp: &Map{Roads: []LineString{
+				{Width: 4, Coords: []Point{{X: 0, Y: 0}, {X: 10, Y: 0}}},
+				{Width: 4, Coords: []Point{{X: 10, Y: 0}, {X: 10, Y: 10}}},
+			}},
+			tolerance: -1,
+			wantNodes: 3,
+			wantEdges: 2,
+		},
+		{
+			name:      "Test data roads",
+			mp:        testMap(t, testFileMap),
+			tolerance: 0.5,
+			wantNodes: 6,
+			wantEdges: 4,
+		},
+		{
+			name:      "No roads",
+			mp:        &Map{},
+			tolerance: 0.5,
+			wantNodes: 0,
+			wantEdges: 0,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			g := NewRoadGraph(test.mp, test.tolerance)
+			if diff := cmp.Diff(len(g.Nodes), test.wantNodes); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+			if diff := cmp.Diff(len(g.Edges), test.wantEdges); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRoadGraph_topology(t *testing.T) {
+	g := NewRoadGraph(testRoadMap(), 0.5)
+
+	want := []Point{
+		{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 20, Y: 0}, {X: 10, Y: 10},
+		{X: 0, Y: 8}, {X: 30, Y: 30}, {X: 40, Y: 30},
+	}
+	if diff := cmp.Diff(g.Nodes, want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(g.Junctions(), []int{1}); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(g.DeadEnds(), []int{2, 5, 6}); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(g.Components(), [][]int{{0, 1, 2, 3, 4}, {5, 6}}); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(g.Nearest(Point{X: 38, Y: 25}), 6); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(g.Edges[0], RoadEdge{From: 0, To: 1, Length: 10, Width: 8, Road: 0}); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestRoadGraph_parallelRoads(t *testing.T) {
+	// Two roads run side by side between the same pair of nodes, which are
+	// then each connected to a single other node.
+	mp := &Map{
+		Roads: []LineString{
+			{Width: 4, Coords: []Point{{X: 0, Y: 0}, {X: 10, Y: 0}}},
+			{Width: 2, Coords: []Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 20, Y: 0}}},
+		},
+	}
+	g := NewRoadGraph(mp, 0)
+
+	if diff := cmp.Diff(len(g.Edges), 3); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(g.Degree(1), 2); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(g.Junctions(), []int{}, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(g.DeadEnds(), []int{0, 2}); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestRoadGraph_ShortestPath(t *testing.T) {
+	g := NewRoadGraph(testRoadMap(), 0.5)
+	tests := []struct {
+		name       string
+		from, to   int
+		wantPath   []int
+		wantLength float64
+		wantErr    error
+	}{
+		{
+			name:       "Around the loop",
+			from:       4,
+			to:         2,
+			wantPath:   []int{4, 0, 1, 2},
+			wantLength: 28,
+		},
+		{
+			name:       "Through the junction",
+			from:       3,
+			to:         2,
+			wantPath:   []int{3, 1, 2},
+			wantLength: 20,
+		},
+		{
+			name:       "Same node",
+			from:       5,
+			to:         5,
+			wantPath:   []int{5},
+			wantLength: 0,
+		},
+		{
+			name:    "Disconnected",
+			from:    0,
+			to:      6,
+			wantErr: ErrNoPath,
+		},
+		{
+			name:    "Out of range",
+			from:    0,
+			to:      7,
+			wantErr: ErrNodeRange,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			path, length, err := g.ShortestPath(test.from, test.to)
+			if err != test.wantErr {
+				t.Fatalf("got: <%v>, want error: <%v>", err, test.wantErr)
+			}
+
+			if diff := cmp.Diff(path, test.wantPath); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(length, test.wantLength, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package mfcg
+
+import (
+	"image/color"
+	"math"
+)
+
+// idTowers names the layer of towers drawn along a map's walls. Towers have
+// no feature of their own in MFCG's data.
+const idTowers string = "towers"
+
+// LayerStyle describes how the shapes of a single layer are drawn.
+type LayerStyle struct {
+	Fill        color.Color // Fill is the interior color. A nil Fill leaves shapes unfilled.
+	Stroke      color.Color // Stroke is the outline color. A nil Stroke leaves shapes unstroked.
+	StrokeWidth float64     // StrokeWidth is the width of outlines, or of lines without a width of their own.
+	Hidden      bool        // Hidden layers are not drawn.
+}
+
+// Style describes how each layer of a Map is drawn. Layers are drawn in
+// MFCG's stacking order, which matches the order of the fields below.
+type Style struct {
+	Background color.Color
+	Earth      LayerStyle
+	Water      LayerStyle
+	Fields     LayerStyle
+	Greens     LayerStyle
+	Rivers     LayerStyle
+	Planks     LayerStyle
+	Roads      LayerStyle
+	Squares    LayerStyle
+	Trees      LayerStyle
+	Walls      LayerStyle
+	Towers     LayerStyle
+	Buildings  LayerStyle
+	Prisms     LayerStyle
+
+	// TreeRadius is the radius of the disc drawn for each tree.
+	TreeRadius float64
+}
+
+// DefaultStyle returns a Style resembling MFCG's default palette.
+func DefaultStyle() *Style {
+	paper := color.RGBA{R: 0xcc, G: 0xc5, B: 0xb8, A: 0xff}
+	ink := color.RGBA{R: 0x1a, G: 0x19, B: 0x17, A: 0xff}
+	water := color.RGBA{R: 0x9a, G: 0xae, B: 0xae, A: 0xff}
+	green := color.RGBA{R: 0xa4, G: 0xad, B: 0x8a, A: 0xff}
+	field := color.RGBA{R: 0xc0, G: 0xbb, B: 0xa0, A: 0xff}
+	road := color.RGBA{R: 0xe6, G: 0xe0, B: 0xd3, A: 0xff}
+	roof := color.RGBA{R: 0xa0, G: 0x98, B: 0x8c, A: 0xff}
+	tree := color.RGBA{R: 0x7d, G: 0x8a, B: 0x66, A: 0xff}
+
+	return &Style{
+		Background: paper,
+		Earth:      LayerStyle{Fill: paper},
+		Water:      LayerStyle{Fill: water},
+		Fields:     LayerStyle{Fill: field, Stroke: ink, StrokeWidth: 0.5},
+		Greens:     LayerStyle{Fill: green},
+		Rivers:     LayerStyle{Stroke: water},
+		Planks:     LayerStyle{Stroke: roof},
+		Roads:      LayerStyle{Stroke: road},
+		Squares:    LayerStyle{Fill: road},
+		Trees:      LayerStyle{Fill: tree, Stroke: ink, StrokeWidth: 0.5},
+		Walls:      LayerStyle{Stroke: ink},
+		Towers:     LayerStyle{Fill: ink},
+		Buildings:  LayerStyle{Fill: roof, Stroke: ink, StrokeWidth: 1},
+		Prisms:     LayerStyle{Fill: ink},
+		TreeRadius: 3,
+	}
+}
+
+// canvas is a drawing surface for the shapes of a Map.
+type canvas interface {
+	// layer begins a new layer drawn with the provided LayerStyle. The
+	// shapes that follow belong to it.
+	layer(id string, s LayerStyle)
+	// polygon draws an area bounded by the provided rings using the
+	// even-odd rule.
+	polygon(rings [][]Point)
+	// polyline draws a line of the provided width through the provided
+	// Points.
+	polyline(pts []Point, width float64)
+	// circle draws a disc of the provided radius around c.
+	circle(c Point, radius float64)
+}
+
+// draw draws each visible layer of the Map onto c in MFCG's stacking order.
+func (m *Map) draw(c canvas, s *Style) {
+	if m.Earth.Coords != nil {
+		drawPolygons(c, IDEarth, s.Earth, []Polygon{m.Earth})
+	}
+	drawPolygons(c, IDWater, s.Water, m.Water)
+	drawPolygons(c, IDFields, s.Fields, m.Fields)
+	drawPolygons(c, IDGreens, s.Greens, m.Greens)
+	drawLineStrings(c, IDRivers, s.Rivers, m.Rivers)
+	drawLineStrings(c, IDPlanks, s.Planks, m.Planks)
+	drawLineStrings(c, IDRoads, s.Roads, m.Roads)
+	drawPolygons(c, IDSquares, s.Squares, m.Squares)
+
+	if !s.Trees.Hidden && len(m.Trees) > 0 && s.TreeRadius > 0 {
+		c.layer(IDTrees, s.Trees)
+		for _, pt := range m.Trees {
+			c.circle(pt, s.TreeRadius)
+		}
+	}
+
+	if !s.Walls.Hidden && len(m.Walls) > 0 {
+		ls := s.Walls
+		ls.Fill = nil
+		c.layer(IDWalls, ls)
+		for _, p := range m.Walls {
+			width := m.WallThickness
+			if width == 0 {
+				width = p.Width
+			}
+			if width == 0 {
+				width = ls.StrokeWidth
+			}
+			for _, ring := range p.Coords {
+				c.polyline(m.wallPath(ring), width)
+			}
+		}
+	}
+
+	if !s.Towers.Hidden && len(m.Walls) > 0 && m.TowerRadius > 0 {
+		c.layer(idTowers, s.Towers)
+		for _, pt := range m.towers() {
+			c.circle(pt, m.TowerRadius)
+		}
+	}
+
+	drawPolygons(c, IDBuildings, s.Buildings, m.Buildings)
+	drawPolygons(c, IDPrisms, s.Prisms, m.Prisms)
+}
+
+// wallPath returns the line drawn along the provided ring of one of the
+// Map's walls, which is closed unless the Map's walls are open.
+func (m *Map) wallPath(ring []Point) []Point {
+	if m.OpenWalls {
+		return ring
+	}
+
+	return closeRing(ring)
+}
+
+// towers returns the location of each tower along the Map's walls. MFCG
+// places a tower at every vertex of a wall. The ends of an open wall which
+// does not return to its start were cut by Clip and hold no tower.
+func (m *Map) towers() []Point {
+	seen := make(map[Point]bool)
+	var pts []Point
+	for _, p := range m.Walls {
+		for _, ring := range p.Coords {
+			if m.OpenWalls && len(ring) > 1 && !isClosed(ring) {
+				ring = ring[1 : len(ring)-1]
+			}
+			for _, pt := range ring {
+				if seen[pt] {
+					continue
+				}
+				seen[pt] = true
+				pts = append(pts, pt)
+			}
+		}
+	}
+
+	return pts
+}
+
+// drawPolygons draws the provided Polygons onto c as a single layer.
+func drawPolygons(c canvas, id string, s LayerStyle, polys []Polygon) {
+	if s.Hidden || len(polys) == 0 {
+		return
+	}
+
+	c.layer(id, s)
+	for _, p := range polys {
+		c.polygon(p.Coords)
+	}
+}
+
+// drawLineStrings draws the provided LineStrings onto c as a single layer.
+// Each line is stroked using its own width.
+func drawLineStrings(c canvas, id string, s LayerStyle, lines []LineString) {
+	if s.Hidden || len(lines) == 0 {
+		return
+	}
+
+	s.Fill = nil
+	c.layer(id, s)
+	for _, ln := range lines {
+		width := ln.Width
+		if width == 0 {
+			width = s.StrokeWidth
+		}
+		c.polyline(ln.Coords, width)
+	}
+}
+
+// extent returns the bounds of the Map grown by its widest line or tower so
+// that strokes are not cut off. The extent of an empty Map is the zero Rect.
+func (m *Map) extent() Rect {
+	r := m.Bounds()
+	if r.Empty() {
+		return Rect{}
+	}
+
+	pad := math.Max(m.TowerRadius, m.WallThickness/2)
+	for _, l := range m.lineLayers() {
+		for _, ln := range l.lines {
+			pad = math.Max(pad, ln.Width/2)
+		}
+	}
+
+	return r.Inset(-pad)
+}
@@ -0,0 +1,78 @@
+package mfcg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMap_towers(t *testing.T) {
+	walls := []Polygon{
+		{Coords: [][]Point{{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 0}}}},
+		{Coords: [][]Point{{{X: 4, Y: 4}, {X: 8, Y: 4}, {X: 8, Y: 8}}}},
+	}
+
+	tests := []struct {
+		name string
+		mp   Map
+		want []Point
+	}{
+		{
+			name: "Closed walls",
+			mp:   Map{Walls: walls},
+			want: []Point{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 8, Y: 4}, {X: 8, Y: 8}},
+		},
+		{
+			name: "Open walls",
+			mp:   Map{Walls: walls, OpenWalls: true},
+			want: []Point{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 8, Y: 4}},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if diff := cmp.Diff(test.mp.towers(), test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMap_extent(t *testing.T) {
+	tests := []struct {
+		name string
+		mp   Map
+		want Rect
+	}{
+		{
+			name: "Empty map",
+			mp:   Map{},
+			want: Rect{},
+		},
+		{
+			name: "Padded by widest line",
+			mp: Map{
+				Earth: Polygon{Coords: [][]Point{{{X: -10, Y: -5}, {X: 10, Y: 5}}}},
+				Roads: []LineString{{Width: 4, Coords: []Point{{X: 0, Y: 0}, {X: 12, Y: 0}}}},
+			},
+			want: Rect{Min: Point{X: -12, Y: -7}, Max: Point{X: 14, Y: 7}},
+		},
+		{
+			name: "Padded by towers",
+			mp: Map{
+				MetaData:  MetaData{TowerRadius: 3},
+				Buildings: []Polygon{{Coords: [][]Point{{{X: 1, Y: 1}, {X: 2, Y: 2}}}}},
+			},
+			want: Rect{Min: Point{X: -2, Y: -2}, Max: Point{X: 5, Y: 5}},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := test.mp.extent()
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package mfcg
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteSVG writes the Map to w as an SVG image drawn using the provided
+// Style. A nil Style is replaced by DefaultStyle. The image's viewBox spans
+// every feature of the Map.
+func (m *Map) WriteSVG(w io.Writer, s *Style) error {
+	if s == nil {
+		s = DefaultStyle()
+	}
+
+	r := m.extent()
+	c := &svgCanvas{w: bufio.NewWriter(w)}
+
+	fmt.Fprintf(c.w, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%s %s %s %s">`,
+		svgNumber(r.Min.X), svgNumber(r.Min.Y), svgNumber(r.Dx()), svgNumber(r.Dy()))
+	c.w.WriteString("\n")
+
+	if s.Background != nil {
+		fmt.Fprintf(c.w, `<rect x="%s" y="%s" width="%s" height="%s"%s/>`,
+			svgNumber(r.Min.X), svgNumber(r.Min.Y), svgNumber(r.Dx()), svgNumber(r.Dy()),
+			svgPaint("fill", s.Background))
+		c.w.WriteString("\n")
+	}
+
+	m.draw(c, s)
+	c.end()
+	c.w.WriteString("</svg>\n")
+
+	return c.w.Flush()
+}
+
+// svgCanvas is a canvas writing SVG elements. Each layer is written as a
+// group carrying the layer's style.
+type svgCanvas struct {
+	w    *bufio.Writer
+	open bool
+}
+
+// layer implements canvas.
+func (c *svgCanvas) layer(id string, s LayerStyle) {
+	c.end()
+
+	fmt.Fprintf(c.w, `<g id="%s"%s%s`, id, svgPaint("fill", s.Fill), svgPaint("stroke", s.Stroke))
+	if s.Stroke != nil && s.StrokeWidth > 0 {
+		fmt.Fprintf(c.w, ` stroke-width="%s"`, svgNumber(s.StrokeWidth))
+	}
+	c.w.WriteString(` fill-rule="evenodd" stroke-linecap="round" stroke-linejoin="round">` + "\n")
+	c.open = true
+}
+
+// end closes the group of the current layer, if any.
+func (c *svgCanvas) end() {
+	if c.open {
+		c.w.WriteString("</g>\n")
+		c.open = false
+	}
+}
+
+// polygon implements canvas.
+func (c *svgCanvas) polygon(rings [][]Point) {
+	var d strings.Builder
+	for _, ring := range rings {
+		if len(ring) == 0 {
+			continue
+		}
+		d.WriteString(svgPath(ring))
+		d.WriteString("Z")
+	}
+	if d.Len() == 0 {
+		return
+	}
+
+	fmt.Fprintf(c.w, `<path d="%s"/>`+"\n", d.String())
+}
+
+// polyline implements canvas.
+func (c *svgCanvas) polyline(pts []Point, width float64) {
+	if len(pts) == 0 {
+		return
+	}
+
+	fmt.Fprintf(c.w, `<path d="%s" stroke-width="%s"/>`+"\n", svgPath(pts), svgNumber(width))
+}
+
+// circle implements canvas.
+func (c *svgCanvas) circle(pt Point, radius float64) {
+	fmt.Fprintf(c.w, `<circle cx="%s" cy="%s" r="%s"/>`+"\n", svgNumber(pt.X), svgNumber(pt.Y), svgNumber(radius))
+}
+
+// svgPath returns the SVG path data moving to the first of the provided
+// Points and drawing lines through the rest.
+func svgPath(pts []Point) string {
+	var d strings.Builder
+	for i, pt := range pts {
+		if i == 0 {
+			d.WriteString("M")
+		} else {
+			d.WriteString("L")
+		}
+		d.WriteString(svgNumber(pt.X))
+		d.WriteString(" ")
+		d.WriteString(svgNumber(pt.Y))
+	}
+
+	return d.String()
+}
+
+// svgPaint returns the SVG attributes painting the named property with the
+// provided color. A nil color disables the property.
+func svgPaint(name string, clr color.Color) string {
+	if clr == nil {
+		return fmt.Sprintf(` %s="none"`, name)
+	}
+
+	c := color.NRGBAModel.Convert(clr).(color.NRGBA)
+	attr := fmt.Sprintf(` %s="#%02x%02x%02x"`, name, c.R, c.G, c.B)
+	if c.A != 0xff {
+		attr += fmt.Sprintf(` %s-opacity="%s"`, name, svgNumber(float64(c.A)/0xff))
+	}
+
+	return attr
+}
+
+// svgNumber formats the provided number as compactly as possible.
+func svgNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
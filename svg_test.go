@@ -0,0 +1,224 @@
+package mfcg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMap_WriteSVG(t *testing.T) {
+	mp := testMap(t, testFileMap)
+
+	hidden := DefaultStyle()
+	hidden.Buildings.Hidden = true
+	hidden.Towers.Hidden = true
+
+	tests := []struct {
+		name       string
+		style      *Style
+		wantGroups []string
+	}{
+		{
+			name:  "Default style",
+			style: nil,
+			wantGroups: []string{
+				IDEarth, IDWater, IDFields, IDRivers, IDPlanks, IDRoads, IDSquares,
+				IDWalls, idTowers, IDBuildings, IDPrisms,
+			},
+		},
+		{
+			name:  "Hidden layers",
+			style: hidden,
+			wantGroups: []string{
+				IDEarth, IDWater, IDFields, IDRivers, IDPlanks, IDRoads, IDSquares,
+				IDWalls, IDPrisms,
+			},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := mp.WriteSVG(&buf, test.style); err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+
+			var got struct {
+				ViewBox string `xml:"viewBox,attr"`
+				Groups  []struct {
+					ID    string `xml:"id,attr"`
+					Paths []struct {
+						D string `xml:"d,attr"`
+					} `xml:"path"`
+				} `xml:"g"`
+			}
+			if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("invalid SVG: %v", err)
+			}
+
+			var groups []string
+			for _, g := range got.Groups {
+				groups = append(groups, g.ID)
+			}
+
+			if diff := cmp.Diff(groups, test.wantGroups); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+
+			if got.ViewBox == "" {
+				t.Errorf("got: <%v>, want non-empty viewBox", got.ViewBox)
+			}
+		})
+	}
+}
+
+func TestMap_WriteSVG_trees(t *testing.T) {
+	mp := testMap(t, testFileTrees)
+
+	var buf bytes.Buffer
+	if err := mp.WriteSVG(&buf, nil); err != nil {
+		t.Fatalf("got: <%v>, want error: <%v>", err, false)
+	}
+
+	var got struct {
+		Groups []struct {
+			ID      string `xml:"id,attr"`
+			Circles []struct {
+				R string `xml:"r,attr"`
+			} `xml:"circle"`
+		} `xml:"g"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid SVG: %v", err)
+	}
+
+	if len(got.Groups) != 2 || got.Groups[1].ID != IDTrees {
+		t.Fatalf("got: <%+v>, want greens and trees groups", got.Groups)
+	}
+
+	if diff := cmp.Diff(len(got.Groups[1].Circles), len(mp.Trees)); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestMap_WriteSVG_openWalls(t *testing.T) {
+	tests := []struct {
+		name string
+		open bool
+		want string
+	}{
+		{
+			name: "Closed walls",
+			open: false,
+			want: "M0 0L10 0L10 10L0 0",
+		},
+		{
+			name: "Open walls",
+			open: true,
+			want: "M0 0L10 0L10 10",
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			mp := &Map{
+				MetaData:  MetaData{WallThickness: 2},
+				Walls:     []Polygon{{Coords: [][]Point{{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}}}},
+				OpenWalls: test.open,
+			}
+
+			var buf bytes.Buffer
+			if err := mp.WriteSVG(&buf, nil); err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+
+			var got struct {
+				Groups []struct {
+					ID    string `xml:"id,attr"`
+					Paths []struct {
+						D string `xml:"d,attr"`
+					} `xml:"path"`
+				} `xml:"g"`
+			}
+			if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("invalid SVG: %v", err)
+			}
+
+			if len(got.Groups) == 0 || got.Groups[0].ID != IDWalls || len(got.Groups[0].Paths) != 1 {
+				t.Fatalf("got: <%+v>, want a single wall path", got.Groups)
+			}
+			if diff := cmp.Diff(got.Groups[0].Paths[0].D, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_svgPath(t *testing.T) {
+	tests := []struct {
+		name string
+		pts  []Point
+		want string
+	}{
+		{
+			name: "Single point",
+			pts:  []Point{{X: 1, Y: 2}},
+			want: "M1 2",
+		},
+		{
+			name: "Multiple points",
+			pts:  []Point{{X: 1.5, Y: -2}, {X: 3, Y: 4.25}, {X: -5, Y: 6}},
+			want: "M1.5 -2L3 4.25L-5 6",
+		},
+		{
+			name: "No points",
+			pts:  nil,
+			want: "",
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := svgPath(test.pts)
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_svgPaint(t *testing.T) {
+	tests := []struct {
+		name  string
+		color color.Color
+		want  string
+	}{
+		{
+			name:  "Opaque color",
+			color: color.RGBA{R: 0xcc, G: 0xc5, B: 0xb8, A: 0xff},
+			want:  ` fill="#ccc5b8"`,
+		},
+		{
+			name:  "Translucent color",
+			color: color.NRGBA{R: 0xff, G: 0x00, B: 0x80, A: 0x33},
+			want:  ` fill="#ff0080" fill-opacity="0.2"`,
+		},
+		{
+			name:  "Nil color",
+			color: nil,
+			want:  ` fill="none"`,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := svgPaint("fill", test.color)
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package mfcg
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Mesh is an indexed triangle mesh, as consumed by 3D engines and GPUs.
+type Mesh struct {
+	Vertices []Point // Vertices holds every vertex of the mesh.
+	Indices  []int   // Indices holds three indices into Vertices per triangle, wound counterclockwise on a y-up plane.
+	Sources  []int   // Sources holds the index within its layer of the feature each triangle was cut from.
+}
+
+// Triangulate splits the Polygon into triangles by ear clipping, after
+// bridging each hole to the exterior. It returns the Polygon's vertices,
+// exterior first and then each hole, along with three indices into them per
+// triangle. Triangles are wound counterclockwise on a y-up plane. Repeated
+// Points and closing Points are left out of the vertices.
+func (p Polygon) Triangulate() ([]Point, []int) {
+	var verts []Point
+	var rings [][]int
+	for i, ring := range p.Coords {
+		pts := orientedRing(ring, i == 0)
+		if len(pts) < 3 {
+			if i == 0 {
+				return nil, nil
+			}
+			continue
+		}
+
+		idx := make([]int, len(pts))
+		for j := range pts {
+			idx[j] = len(verts) + j
+		}
+		verts = append(verts, pts...)
+		rings = append(rings, idx)
+	}
+	if len(rings) == 0 {
+		return nil, nil
+	}
+
+	outer := rings[0]
+	holes := rings[1:]
+	sort.Slice(holes, func(i, j int) bool {
+		return verts[rightmost(verts, holes[i])].X > verts[rightmost(verts, holes[j])].X
+	})
+	for _, hole := range holes {
+		outer = bridgeHole(verts, outer, hole)
+	}
+
+	return verts, earClip(verts, outer)
+}
+
+// TriangulateLayer triangulates every Polygon of the layer with the
+// provided ID into a single Mesh. Each triangle's source is the index of its
+// Polygon within the layer. The returned error wraps ErrUnknownLayer if the
+// ID does not name a layer of Polygons, as is the case of open walls.
+func (m *Map) TriangulateLayer(id string) (Mesh, error) {
+	var polys []Polygon
+	found := id == IDEarth // The Earth is only listed if it has coordinates.
+	for _, l := range m.polygonLayers() {
+		if l.id == id {
+			polys, found = l.polys, true
+		}
+	}
+	if id == IDWalls && m.OpenWalls {
+		return Mesh{}, fmt.Errorf("%w: %q holds open paths", ErrUnknownLayer, id)
+	}
+	if !found {
+		return Mesh{}, fmt.Errorf("%w: %q is not a layer of Polygons", ErrUnknownLayer, id)
+	}
+
+	var mesh Mesh
+	for i, p := range polys {
+		verts, indices := p.Triangulate()
+		base := len(mesh.Vertices)
+		mesh.Vertices = append(mesh.Vertices, verts...)
+		for _, n := range indices {
+			mesh.Indices = append(mesh.Indices, base+n)
+		}
+		for j := 0; j < len(indices)/3; j++ {
+			mesh.Sources = append(mesh.Sources, i)
+		}
+	}
+
+	return mesh, nil
+}
+
+// orientedRing returns a copy of the provided ring without repeated or
+// closing Points, wound counterclockwise on a y-up plane if outer is true and
+// clockwise otherwise.
+func orientedRing(ring []Point, outer bool) []Point {
+	pts, _ := removeDuplicates(append([]Point(nil), openRing(ring)...))
+	for len(pts) > 1 && pts[len(pts)-1] == pts[0] {
+		pts = pts[:len(pts)-1]
+	}
+
+	if a := signedArea(pts); (a < 0) == outer {
+		for l, r := 0, len(pts)-1; l < r; l, r = l+1, r-1 {
+			pts[l], pts[r] = pts[r], pts[l]
+		}
+	}
+
+	return pts
+}
+
+// rightmost returns the element of ring indexing the vertex with the
+// largest X.
+func rightmost(verts []Point, ring []int) int {
+	best := ring[0]
+	for _, n := range ring {
+		if verts[n].X > verts[best].X {
+			best = n
+		}
+	}
+
+	return best
+}
+
+// bridgeHole returns the counterclockwise ring outer joined to the clockwise
+// ring hole through a pair of coincident edges, so the result can be
+// triangulated as a single ring. The bridge links the hole's rightmost
+// vertex to a vertex of outer visible from it. If no such vertex is found,
+// outer is returned unchanged.
+func bridgeHole(verts []Point, outer, hole []int) []int {
+	mi := 0
+	for i, n := range hole {
+		if verts[n].X > verts[hole[mi]].X {
+			mi = i
+		}
+	}
+	m := verts[hole[mi]]
+
+	// Cast a ray from m towards +X and find the closest edge of outer it
+	// hits. The edge's endpoint furthest along the ray is visible from m
+	// unless a reflex vertex of outer lies in the way.
+	hitX, at := math.Inf(1), -1
+	for i := range outer {
+		a, b := verts[outer[i]], verts[outer[(i+1)%len(outer)]]
+		// Only edges facing m count, which skips the far copy of a bridge.
+		if math.Min(a.Y, b.Y) > m.Y || math.Max(a.Y, b.Y) < m.Y || a.Y == b.Y || orient(a, b, m) < 0 {
+			continue
+		}
+
+		x := a.X + (m.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+		if x < m.X || x >= hitX {
+			continue
+		}
+		hitX = x
+		at = i
+		if b.X > a.X {
+			at = (i + 1) % len(outer)
+		}
+	}
+	if at < 0 {
+		return outer
+	}
+
+	hit := Point{X: hitX, Y: m.Y}
+	p := verts[outer[at]]
+	if p != hit {
+		// Any reflex vertex within the triangle m, hit, p hides p from m; the
+		// one closest in angle to the ray is visible instead.
+		tri := [3]Point{m, hit, p}
+		if orient(m, hit, p) < 0 {
+			tri[1], tri[2] = p, hit
+		}
+
+		bestAngle, bestDist := math.Inf(1), math.Inf(1)
+		for i, n := range outer {
+			r := verts[n]
+			prev, next := verts[outer[(i+len(outer)-1)%len(outer)]], verts[outer[(i+1)%len(outer)]]
+			if orient(prev, r, next) >= 0 || !inTriangle(r, tri[0], tri[1], tri[2]) {
+				continue
+			}
+			// A vertex repeated by an earlier bridge is only visible from m
+			// through the copy whose corner opens towards m.
+			if orient(prev, r, m) <= 0 && orient(r, next, m) <= 0 {
+				continue
+			}
+
+			angle := math.Abs(math.Atan2(r.Y-m.Y, r.X-m.X))
+			if d := distance(r, m); angle < bestAngle || (angle == bestAngle && d < bestDist) {
+				at, bestAngle, bestDist = i, angle, d
+			}
+		}
+	}
+
+	joined := make([]int, 0, len(outer)+len(hole)+2)
+	joined = append(joined, outer[:at+1]...)
+	for i := range hole {
+		joined = append(joined, hole[(mi+i)%len(hole)])
+	}
+	joined = append(joined, hole[mi], outer[at])
+	joined = append(joined, outer[at+1:]...)
+
+	return joined
+}
+
+// earClip triangulates the counterclockwise ring of vertex indices by
+// repeatedly cutting off an ear: a convex corner whose triangle contains no
+// other vertex of the ring.
+func earClip(verts []Point, ring []int) []int {
+	ring = append([]int(nil), ring...)
+	var tris []int
+
+	for len(ring) > 3 {
+		n := len(ring)
+		cut := -1
+		for i := 0; i < n && cut < 0; i++ {
+			a, b, c := verts[ring[(i+n-1)%n]], verts[ring[i]], verts[ring[(i+1)%n]]
+			if orient(a, b, c) <= 0 {
+				continue
+			}
+
+			ear := true
+			for j := 0; j < n && ear; j++ {
+				pt := verts[ring[j]]
+				if pt == a || pt == b || pt == c {
+					continue
+				}
+				ear = !inTriangle(pt, a, b, c)
+			}
+			if ear {
+				cut = i
+			}
+		}
+
+		if cut < 0 {
+			// No ear remains in a degenerate ring. Drop a vertex without a
+			// corner, or any vertex as a last resort, so clipping ends.
+			cut = 0
+			for i := 0; i < n; i++ {
+				if orient(verts[ring[(i+n-1)%n]], verts[ring[i]], verts[ring[(i+1)%n]]) == 0 {
+					cut = i
+					break
+				}
+			}
+			ring = append(ring[:cut], ring[cut+1:]...)
+			continue
+		}
+
+		tris = append(tris, ring[(cut+n-1)%n], ring[cut], ring[(cut+1)%n])
+		ring = append(ring[:cut], ring[cut+1:]...)
+	}
+
+	if len(ring) == 3 && orient(verts[ring[0]], verts[ring[1]], verts[ring[2]]) > 0 {
+		tris = append(tris, ring...)
+	}
+
+	return tris
+}
+
+// inTriangle reports whether Point p lies within or on the counterclockwise
+// triangle abc.
+func inTriangle(p, a, b, c Point) bool {
+	return orient(a, b, p) >= 0 && orient(b, c, p) >= 0 && orient(c, a, p) >= 0
+}
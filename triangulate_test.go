@@ -0,0 +1,148 @@
+package mfcg
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPolygon_Triangulate(t *testing.T) {
+	tests := []struct {
+		name      string
+		p         Polygon
+		wantVerts int
+		wantTris  int
+	}{
+		{
+			name:      "Triangle",
+			p:         Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}}}},
+			wantVerts: 3,
+			wantTris:  1,
+		},
+		{
+			name:      "Clockwise closed square",
+			p:         Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 0, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 0}, {X: 0, Y: 0}}}},
+			wantVerts: 4,
+			wantTris:  2,
+		},
+		{
+			name: "Concave",
+			p: Polygon{Coords: [][]Point{{
+				{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 5, Y: 2}, {X: 0, Y: 10},
+			}}},
+			wantVerts: 5,
+			wantTris:  3,
+		},
+		{
+			name: "Hole",
+			p: Polygon{Coords: [][]Point{
+				{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}},
+				{{X: 4, Y: 4}, {X: 6, Y: 4}, {X: 6, Y: 6}, {X: 4, Y: 6}},
+			}},
+			wantVerts: 8,
+			wantTris:  8,
+		},
+		{
+			name: "Holes hiding each other",
+			p: Polygon{Coords: [][]Point{
+				{{X: 0, Y: 0}, {X: 30, Y: 0}, {X: 30, Y: 10}, {X: 0, Y: 10}},
+				{{X: 2, Y: 4}, {X: 8, Y: 4}, {X: 8, Y: 6}, {X: 2, Y: 6}},
+				{{X: 12, Y: 3}, {X: 18, Y: 3}, {X: 18, Y: 7}, {X: 12, Y: 7}},
+				{{X: 22, Y: 2}, {X: 26, Y: 5}, {X: 22, Y: 8}},
+			}},
+			wantVerts: 15,
+			wantTris:  19,
+		},
+		{
+			name: "Reflex vertex near the bridge",
+			p: Polygon{Coords: [][]Point{
+				{{X: 0, Y: 0}, {X: 20, Y: 0}, {X: 20, Y: 20}, {X: 12, Y: 11}, {X: 14, Y: 20}, {X: 0, Y: 20}},
+				{{X: 4, Y: 8}, {X: 8, Y: 8}, {X: 8, Y: 10}, {X: 4, Y: 10}},
+			}},
+			wantVerts: 10,
+			wantTris:  10,
+		},
+		{
+			name: "Hole bridged across another bridge",
+			p: Polygon{Coords: [][]Point{
+				{{X: 0, Y: 0}, {X: 20, Y: 0}, {X: 22, Y: 10}, {X: 0, Y: 10}},
+				{{X: 10, Y: 4}, {X: 12, Y: 4}, {X: 12, Y: 6}, {X: 10, Y: 6}},
+				{{X: 2, Y: 7}, {X: 6, Y: 8}, {X: 2, Y: 9}},
+			}},
+			wantVerts: 11,
+			wantTris:  13,
+		},
+		{
+			name:      "Degenerate",
+			p:         Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 1}}}},
+			wantVerts: 0,
+			wantTris:  0,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			verts, indices := test.p.Triangulate()
+			if diff := cmp.Diff(len(verts), test.wantVerts); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+			if diff := cmp.Diff(len(indices), 3*test.wantTris); diff != "" {
+				t.Fatalf("mismatch (-got +want):\n%s", diff)
+			}
+
+			var area float64
+			for i := 0; i < len(indices); i += 3 {
+				a := signedArea([]Point{verts[indices[i]], verts[indices[i+1]], verts[indices[i+2]]})
+				if a <= 0 {
+					t.Errorf("got: <%v>, want: <%s>", a, "positive area")
+				}
+				area += a
+			}
+
+			if math.Abs(area-test.p.Area()) > 1e-9 {
+				t.Errorf("got: <%v>, want: <%v>", area, test.p.Area())
+			}
+		})
+	}
+}
+
+func TestMap_TriangulateLayer(t *testing.T) {
+	mp := testMap(t, testFileDistricts)
+
+	mesh, err := mp.TriangulateLayer(IDBuildings)
+	if err != nil {
+		t.Fatalf("got: <%v>, want error: <%v>", err, false)
+	}
+
+	want := Mesh{
+		Vertices: []Point{
+			{X: -40, Y: -40}, {X: -30, Y: -40}, {X: -30, Y: -30}, {X: -40, Y: -30},
+			{X: 20, Y: 20}, {X: 30, Y: 20}, {X: 30, Y: 30}, {X: 20, Y: 30},
+			{X: 200, Y: 200}, {X: 210, Y: 200}, {X: 210, Y: 210},
+		},
+		Indices: []int{3, 0, 1, 1, 2, 3, 7, 4, 5, 5, 6, 7, 8, 9, 10},
+		Sources: []int{0, 0, 1, 1, 2},
+	}
+	if diff := cmp.Diff(mesh, want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	mesh, err = mp.TriangulateLayer(IDEarth)
+	if err != nil || len(mesh.Indices) != 0 {
+		t.Errorf("got: <%v>, want error: <%v>", err, false)
+	}
+
+	for _, id := range []string{IDRoads, IDDistricts, "lanterns"} {
+		if _, err := mp.TriangulateLayer(id); !errors.Is(err, ErrUnknownLayer) {
+			t.Errorf("got: <%v>, want error: <%v>", err, ErrUnknownLayer)
+		}
+	}
+
+	mp.OpenWalls = true
+	if _, err := mp.TriangulateLayer(IDWalls); !errors.Is(err, ErrUnknownLayer) {
+		t.Errorf("got: <%v>, want error: <%v>", err, ErrUnknownLayer)
+	}
+}
@@ -0,0 +1,263 @@
+package mfcg
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// IssueKind describes a problem found in a Map's geometry.
+type IssueKind string
+
+// Kinds of Issues reported by Validate.
+const (
+	IssueInvalidCoordinate IssueKind = "invalid coordinate"
+	IssueShortRing         IssueKind = "ring with fewer than 3 points"
+	IssueRingClosure       IssueKind = "ring closure inconsistent with map"
+	IssueSelfIntersection  IssueKind = "self-intersecting ring"
+	IssueWinding           IssueKind = "hole wound like its exterior"
+	IssueHoleOutside       IssueKind = "hole outside its exterior"
+	IssueZeroArea          IssueKind = "zero-area building"
+	IssueShortLine         IssueKind = "line with fewer than 2 points"
+)
+
+// epsilon is the distance under which Points are considered coincident and
+// the area under which a Polygon is considered empty.
+const epsilon = 1e-9
+
+// Issue locates a problem found in a Map's geometry. Indices which do not
+// apply to the problem are set to -1.
+type Issue struct {
+	Layer string    // Layer is the ID of the layer containing the problem.
+	Index int       // Index is the position of the feature within its layer.
+	Ring  int       // Ring is the position of the ring within its Polygon.
+	Point int       // Point is the position of the Point within its ring or line.
+	Kind  IssueKind // Kind describes the problem.
+}
+
+// String returns a description of the Issue and its location.
+func (is Issue) String() string {
+	loc := []string{fmt.Sprintf("%s %d", is.Layer, is.Index)}
+	if is.Ring >= 0 {
+		loc = append(loc, fmt.Sprintf("ring %d", is.Ring))
+	}
+	if is.Point >= 0 {
+		loc = append(loc, fmt.Sprintf("point %d", is.Point))
+	}
+
+	return strings.Join(loc, ", ") + ": " + string(is.Kind)
+}
+
+// Validate checks the geometry of every layer of the Map and returns the
+// Issues found, ordered by layer. Rings are expected to all be either
+// explicitly closed or, as MFCG writes them, left open; whichever convention
+// most rings of the Map follow is expected of the others. Open walls are
+// checked as lines.
+func (m *Map) Validate() []Issue {
+	var issues []Issue
+	closed := m.closedRings()
+
+	for _, l := range m.polygonLayers() {
+		if l.id == IDWalls && m.OpenWalls {
+			issues = append(issues, validatePaths(l.id, l.polys)...)
+			continue
+		}
+
+		for i, p := range l.polys {
+			found := validatePolygon(p, closed)
+			if l.id == IDBuildings && len(found) == 0 && len(p.Coords) > 0 &&
+				math.Abs(signedArea(openRing(p.Coords[0]))) < epsilon {
+				found = append(found, Issue{Ring: -1, Point: -1, Kind: IssueZeroArea})
+			}
+
+			for _, is := range found {
+				is.Layer, is.Index = l.id, i
+				issues = append(issues, is)
+			}
+		}
+	}
+
+	for _, l := range m.lineLayers() {
+		for i, ln := range l.lines {
+			if len(ln.Coords) < 2 {
+				issues = append(issues, Issue{Layer: l.id, Index: i, Ring: -1, Point: -1, Kind: IssueShortLine})
+			}
+			for j, pt := range ln.Coords {
+				if !finite(pt) {
+					issues = append(issues, Issue{Layer: l.id, Index: i, Ring: -1, Point: j, Kind: IssueInvalidCoordinate})
+				}
+			}
+		}
+	}
+
+	for i, pt := range m.Trees {
+		if !finite(pt) {
+			issues = append(issues, Issue{Layer: IDTrees, Index: i, Ring: -1, Point: -1, Kind: IssueInvalidCoordinate})
+		}
+	}
+
+	return issues
+}
+
+// validatePaths returns the Issues found in the provided Polygons of the
+// identified layer, whose rings are checked as lines.
+func validatePaths(id string, polys []Polygon) []Issue {
+	var issues []Issue
+	for i, p := range polys {
+		for r, ring := range p.Coords {
+			if len(ring) < 2 {
+				issues = append(issues, Issue{Layer: id, Index: i, Ring: r, Point: -1, Kind: IssueShortLine})
+			}
+			for j, pt := range ring {
+				if !finite(pt) {
+					issues = append(issues, Issue{Layer: id, Index: i, Ring: r, Point: j, Kind: IssueInvalidCoordinate})
+				}
+			}
+		}
+	}
+
+	return issues
+}
+
+// validatePolygon returns the Issues found in Polygon p without their layer
+// and index. If closed is true, rings are expected to repeat their first
+// Point at their end.
+func validatePolygon(p Polygon, closed bool) []Issue {
+	var issues []Issue
+	valid := make([]bool, len(p.Coords))
+
+	for r, ring := range p.Coords {
+		n := len(issues)
+		for j, pt := range ring {
+			if !finite(pt) {
+				issues = append(issues, Issue{Ring: r, Point: j, Kind: IssueInvalidCoordinate})
+			}
+		}
+		if len(issues) > n {
+			continue
+		}
+
+		open := openRing(ring)
+		if len(open) < 3 {
+			issues = append(issues, Issue{Ring: r, Point: -1, Kind: IssueShortRing})
+			continue
+		}
+		if isClosed(ring) != closed {
+			issues = append(issues, Issue{Ring: r, Point: -1, Kind: IssueRingClosure})
+		}
+		if j := selfIntersection(open); j >= 0 {
+			issues = append(issues, Issue{Ring: r, Point: j, Kind: IssueSelfIntersection})
+		}
+		valid[r] = true
+	}
+
+	if len(p.Coords) == 0 || !valid[0] {
+		return issues
+	}
+
+	outer := openRing(p.Coords[0])
+	outerArea := signedArea(outer)
+	for r, ring := range p.Coords[1:] {
+		if !valid[r+1] {
+			continue
+		}
+
+		hole := openRing(ring)
+		if a := signedArea(hole); (a > 0 && outerArea > 0) || (a < 0 && outerArea < 0) {
+			issues = append(issues, Issue{Ring: r + 1, Point: -1, Kind: IssueWinding})
+		}
+
+		for j, pt := range hole {
+			if !ringContains(outer, pt) && ringDistance(outer, pt) > epsilon {
+				issues = append(issues, Issue{Ring: r + 1, Point: j, Kind: IssueHoleOutside})
+				break
+			}
+		}
+	}
+
+	return issues
+}
+
+// selfIntersection returns the index of the first Point of a segment of the
+// provided open ring which crosses or touches a non-adjacent segment, or -1
+// if the ring is simple. Repeated consecutive Points are ignored.
+func selfIntersection(ring []Point) int {
+	var starts []int
+	for i := range ring {
+		if ring[i] != ring[(i+1)%len(ring)] {
+			starts = append(starts, i)
+		}
+	}
+
+	n := len(starts)
+	for i := 0; i < n; i++ {
+		a, b := ring[starts[i]], ring[(starts[i]+1)%len(ring)]
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n-1 {
+				continue
+			}
+
+			c, d := ring[starts[j]], ring[(starts[j]+1)%len(ring)]
+			if segmentsIntersect(a, b, c, d) {
+				return starts[i]
+			}
+		}
+	}
+
+	return -1
+}
+
+// closedRings reports whether most rings of the Map repeat their first Point
+// at their end. Ties are resolved in favor of open rings, as written by
+// MFCG. Open walls are lines and are not counted.
+func (m *Map) closedRings() bool {
+	var closed, open int
+	for _, l := range m.polygonLayers() {
+		if l.id == IDWalls && m.OpenWalls {
+			continue
+		}
+		for _, p := range l.polys {
+			for _, ring := range p.Coords {
+				if isClosed(ring) {
+					closed++
+				} else {
+					open++
+				}
+			}
+		}
+	}
+
+	return closed > open
+}
+
+// isClosed reports whether the provided ring repeats its first Point at its
+// end.
+func isClosed(ring []Point) bool {
+	return len(ring) > 1 && ring[0] == ring[len(ring)-1]
+}
+
+// openRing returns the provided ring without the Point closing it, if any.
+// The returned ring shares its Points with the provided one.
+func openRing(ring []Point) []Point {
+	if isClosed(ring) {
+		return ring[:len(ring)-1]
+	}
+
+	return ring
+}
+
+// ringDistance returns the euclidean distance between Point pt and the
+// boundary of the provided ring.
+func ringDistance(ring []Point, pt Point) float64 {
+	d := math.Inf(1)
+	for i := range ring {
+		d = math.Min(d, segmentDistance(pt, ring[i], ring[(i+1)%len(ring)]))
+	}
+
+	return d
+}
+
+// finite reports whether neither coordinate of Point pt is NaN or infinite.
+func finite(pt Point) bool {
+	return !math.IsNaN(pt.X) && !math.IsInf(pt.X, 0) && !math.IsNaN(pt.Y) && !math.IsInf(pt.Y, 0)
+}
@@ -0,0 +1,138 @@
+package mfcg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMap_Validate(t *testing.T) {
+	square := []Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	hole := []Point{{X: 2, Y: 2}, {X: 2, Y: 4}, {X: 4, Y: 4}, {X: 4, Y: 2}}
+
+	tests := []struct {
+		name string
+		mp   *Map
+		want []Issue
+	}{
+		{
+			name: "Valid map",
+			mp:   testMap(t, testFileTrees),
+			want: nil,
+		},
+		{
+			name: "Valid polygon with hole",
+			mp:   &Map{Squares: []Polygon{{Coords: [][]Point{square, hole}}}},
+			want: nil,
+		},
+		{
+			name: "Short ring",
+			mp:   &Map{Fields: []Polygon{{Coords: [][]Point{square}}, {Coords: [][]Point{{{X: 1, Y: 1}, {X: 2, Y: 2}}}}}},
+			want: []Issue{{Layer: IDFields, Index: 1, Ring: 0, Point: -1, Kind: IssueShortRing}},
+		},
+		{
+			name: "Closed ring among open rings",
+			mp: &Map{Greens: []Polygon{
+				{Coords: [][]Point{square}},
+				{Coords: [][]Point{square}},
+				{Coords: [][]Point{append(append([]Point{}, square...), square[0])}},
+			}},
+			want: []Issue{{Layer: IDGreens, Index: 2, Ring: 0, Point: -1, Kind: IssueRingClosure}},
+		},
+		{
+			name: "Self-intersecting ring",
+			mp:   &Map{Water: []Polygon{{Coords: [][]Point{{{X: 0, Y: 0}, {X: 10, Y: 10}, {X: 10, Y: 0}, {X: 0, Y: 10}}}}}},
+			want: []Issue{{Layer: IDWater, Index: 0, Ring: 0, Point: 0, Kind: IssueSelfIntersection}},
+		},
+		{
+			name: "Repeated point",
+			mp:   &Map{Water: []Polygon{{Coords: [][]Point{{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}}}}},
+			want: nil,
+		},
+		{
+			name: "Hole wound like exterior",
+			mp:   &Map{Squares: []Polygon{{Coords: [][]Point{square, {{X: 2, Y: 2}, {X: 4, Y: 2}, {X: 4, Y: 4}, {X: 2, Y: 4}}}}}},
+			want: []Issue{{Layer: IDSquares, Index: 0, Ring: 1, Point: -1, Kind: IssueWinding}},
+		},
+		{
+			name: "Hole outside exterior",
+			mp:   &Map{Squares: []Polygon{{Coords: [][]Point{square, {{X: 8, Y: 8}, {X: 8, Y: 12}, {X: 9, Y: 12}, {X: 9, Y: 8}}}}}},
+			want: []Issue{{Layer: IDSquares, Index: 0, Ring: 1, Point: 1, Kind: IssueHoleOutside}},
+		},
+		{
+			name: "Hole touching exterior",
+			mp:   &Map{Squares: []Polygon{{Coords: [][]Point{square, {{X: 0, Y: 2}, {X: 2, Y: 4}, {X: 2, Y: 2}}}}}},
+			want: nil,
+		},
+		{
+			name: "Zero-area building",
+			mp:   &Map{Buildings: []Polygon{{Coords: [][]Point{{{X: 0, Y: 0}, {X: 5, Y: 5}, {X: 10, Y: 10}}}}}},
+			want: []Issue{{Layer: IDBuildings, Index: 0, Ring: -1, Point: -1, Kind: IssueZeroArea}},
+		},
+		{
+			name: "Invalid coordinates",
+			mp: &Map{
+				Earth: Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: math.NaN(), Y: 0}, {X: 10, Y: 10}}}},
+				Roads: []LineString{{Coords: []Point{{X: 0, Y: 0}, {X: 1, Y: math.Inf(1)}}}},
+				Trees: []Point{{X: 1, Y: 1}, {X: math.Inf(-1), Y: 1}},
+			},
+			want: []Issue{
+				{Layer: IDEarth, Index: 0, Ring: 0, Point: 1, Kind: IssueInvalidCoordinate},
+				{Layer: IDRoads, Index: 0, Ring: -1, Point: 1, Kind: IssueInvalidCoordinate},
+				{Layer: IDTrees, Index: 1, Ring: -1, Point: -1, Kind: IssueInvalidCoordinate},
+			},
+		},
+		{
+			name: "Short line",
+			mp:   &Map{Rivers: []LineString{{Coords: []Point{{X: 1, Y: 1}}}}},
+			want: []Issue{{Layer: IDRivers, Index: 0, Ring: -1, Point: -1, Kind: IssueShortLine}},
+		},
+		{
+			name: "Open walls",
+			mp: &Map{
+				Squares:   []Polygon{{Coords: [][]Point{square}}},
+				Walls:     []Polygon{{Coords: [][]Point{{{X: 0, Y: 0}, {X: 10, Y: 0}}, {{X: 5, Y: 5}}, append(append([]Point{}, square...), square[0])}}},
+				OpenWalls: true,
+			},
+			want: []Issue{{Layer: IDWalls, Index: 0, Ring: 1, Point: -1, Kind: IssueShortLine}},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if diff := cmp.Diff(test.mp.Validate(), test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIssue_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		issue Issue
+		want  string
+	}{
+		{
+			name:  "Point issue",
+			issue: Issue{Layer: IDWalls, Index: 2, Ring: 0, Point: 5, Kind: IssueSelfIntersection},
+			want:  "walls 2, ring 0, point 5: self-intersecting ring",
+		},
+		{
+			name:  "Feature issue",
+			issue: Issue{Layer: IDBuildings, Index: 7, Ring: -1, Point: -1, Kind: IssueZeroArea},
+			want:  "buildings 7: zero-area building",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if diff := cmp.Diff(test.issue.String(), test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
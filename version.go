@@ -0,0 +1,155 @@
+package mfcg
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// generatorMFCG is the generator name MFCG writes to its values feature.
+const generatorMFCG = "mfcg"
+
+var (
+	// ErrUnsupportedVersion is returned in strict mode when MFCG data was
+	// written by a version of MFCG this package does not know how to decode.
+	// Otherwise it is reported as a warning and the data is decoded as if
+	// written by the newest supported version.
+	ErrUnsupportedVersion = errors.New("unsupported MFCG version")
+	// ErrUnknownGenerator is reported as a warning when MFCG data names a
+	// generator other than MFCG, such as a tool re-exporting the data. The
+	// data is decoded as if written by the newest supported version.
+	ErrUnknownGenerator = errors.New("unknown MFCG data generator")
+)
+
+// schema describes the layers exported by a series of MFCG releases.
+type schema struct {
+	version [2]int          // version holds the major and minor numbers of the releases.
+	layers  map[string]bool // layers contains the ID of every exported layer.
+}
+
+// series returns the schema's version written as major.minor.
+func (s schema) series() string {
+	return fmt.Sprintf("%d.%d", s.version[0], s.version[1])
+}
+
+// baseLayers contains the layers exported by every supported MFCG release.
+var baseLayers = []string{
+	IDValues,
+	IDEarth,
+	IDPlanks,
+	IDRivers,
+	IDRoads,
+	IDBuildings,
+	IDFields,
+	IDGreens,
+	IDPrisms,
+	IDSquares,
+	IDWalls,
+	IDWater,
+}
+
+// schemas lists every supported series of MFCG releases from oldest to
+// newest. The base layers are those of the MFCG 0.7.7a export this package
+// was first written against. Trees and districts only appear in more recent
+// exports, but as the release adding them is not recorded they are decoded
+// for every series rather than tied to a guessed one. A series is only added
+// once a release is known to export a different set of layers.
+var schemas = []schema{
+	newSchema(0, 7, baseLayers, IDTrees, IDDistricts),
+}
+
+// newSchema returns a schema for the provided series exporting the provided
+// layers.
+func newSchema(major, minor int, layers []string, extra ...string) schema {
+	s := schema{version: [2]int{major, minor}, layers: make(map[string]bool)}
+	for _, id := range layers {
+		s.layers[id] = true
+	}
+	for _, id := range extra {
+		s.layers[id] = true
+	}
+	return s
+}
+
+// SupportedVersions returns the series of MFCG releases that can be decoded,
+// from oldest to newest. Each series is written as major.minor and covers
+// every release sharing that prefix.
+func SupportedVersions() []string {
+	vers := make([]string, len(schemas))
+	for i, s := range schemas {
+		vers[i] = s.series()
+	}
+	return vers
+}
+
+// schemaFor returns the schema used to decode data described by the provided
+// MetaData. Data without a version or written by another generator is
+// decoded with the newest schema and data older than every supported series
+// with the oldest. If the data was written by a newer version of MFCG or its
+// version cannot be parsed, the newest schema is returned along with an
+// error wrapping ErrUnsupportedVersion.
+func schemaFor(md MetaData) (schema, error) {
+	newest := schemas[len(schemas)-1]
+	if md.Version == "" || !fromMFCG(md) {
+		return newest, nil
+	}
+
+	ver, err := parseVersion(md.Version)
+	if err != nil {
+		return newest, fmt.Errorf("%w: %v", ErrUnsupportedVersion, err)
+	}
+
+	if compareVersions(ver, newest.version) > 0 {
+		return newest, fmt.Errorf("%w: version %q is newer than %s", ErrUnsupportedVersion, md.Version, newest.series())
+	}
+
+	match := schemas[0]
+	for _, s := range schemas {
+		if compareVersions(s.version, ver) > 0 {
+			break
+		}
+		match = s
+	}
+
+	return match, nil
+}
+
+// fromMFCG reports whether data described by the provided MetaData was
+// written by MFCG. Data naming no generator is assumed to be.
+func fromMFCG(md MetaData) bool {
+	return md.Generator == "" || md.Generator == generatorMFCG
+}
+
+// parseVersion returns the major and minor numbers of the provided version,
+// such as "0.7.7a". Anything following the minor number is ignored.
+func parseVersion(v string) ([2]int, error) {
+	var ver [2]int
+
+	parts := strings.SplitN(v, ".", 3)
+	if len(parts) < 2 {
+		return ver, fmt.Errorf("cannot parse version %q", v)
+	}
+
+	for i := range ver {
+		digits := strings.TrimRightFunc(parts[i], func(r rune) bool {
+			return r < '0' || r > '9'
+		})
+		n, err := strconv.Atoi(digits)
+		if err != nil || n < 0 {
+			return ver, fmt.Errorf("cannot parse version %q", v)
+		}
+		ver[i] = n
+	}
+
+	return ver, nil
+}
+
+// compareVersions returns a negative number if a precedes b, a positive
+// number if a follows b and zero if they belong to the same series.
+func compareVersions(a, b [2]int) int {
+	if a[0] != b[0] {
+		return a[0] - b[0]
+	}
+	return a[1] - b[1]
+}
@@ -0,0 +1,234 @@
+package mfcg
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	testFileFuture string = "./test_data/mapFutureVersion.json"
+	testFileOld    string = "./test_data/mapOldVersion.json"
+)
+
+func TestSupportedVersions(t *testing.T) {
+	want := []string{"0.7"}
+
+	if diff := cmp.Diff(SupportedVersions(), want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func Test_schemaFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		md      MetaData
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Current version",
+			md:      MetaData{Generator: "mfcg", Version: "0.7.7a"},
+			want:    "0.7",
+			wantErr: false,
+		},
+		{
+			name:    "Version older than every series",
+			md:      MetaData{Generator: "mfcg", Version: "0.6.12"},
+			want:    "0.7",
+			wantErr: false,
+		},
+		{
+			name:    "Missing version and generator",
+			md:      MetaData{},
+			want:    "0.7",
+			wantErr: false,
+		},
+		{
+			name:    "Newer minor version",
+			md:      MetaData{Generator: "mfcg", Version: "0.8.0"},
+			want:    "0.7",
+			wantErr: true,
+		},
+		{
+			name:    "Newer major version",
+			md:      MetaData{Generator: "mfcg", Version: "1.0"},
+			want:    "0.7",
+			wantErr: true,
+		},
+		{
+			name:    "Unparseable version",
+			md:      MetaData{Generator: "mfcg", Version: "latest"},
+			want:    "0.7",
+			wantErr: true,
+		},
+		{
+			name:    "Other generator",
+			md:      MetaData{Generator: "watabou", Version: "0.3"},
+			want:    "0.7",
+			wantErr: false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := schemaFor(test.md)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got: <%v>, want error: <%v>", err, test.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrUnsupportedVersion) {
+				t.Errorf("got: <%v>, want: <%v>", err, ErrUnsupportedVersion)
+			}
+
+			if diff := cmp.Diff(got.series(), test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_parseVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		ver     string
+		want    [2]int
+		wantErr bool
+	}{
+		{"Release with suffix", "0.7.7a", [2]int{0, 7}, false},
+		{"Series", "0.6", [2]int{0, 6}, false},
+		{"Multiple digits", "12.34.5", [2]int{12, 34}, false},
+		{"Minor suffix", "1.2b", [2]int{1, 2}, false},
+		{"Single number", "7", [2]int{}, true},
+		{"Empty", "", [2]int{}, true},
+		{"Letters", "a.b", [2]int{}, true},
+		{"Negative", "-1.2", [2]int{}, true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseVersion(test.ver)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got: <%v>, want error: <%v>", err, test.wantErr)
+			}
+
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNew_version(t *testing.T) {
+	f, err := os.Open(testFileFuture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	mp, err := New(f)
+	if err != nil {
+		t.Fatalf("got: <%v>, want error: <%v>", err, false)
+	}
+
+	if len(mp.Warnings) != 1 || !errors.Is(mp.Warnings[0], ErrUnsupportedVersion) {
+		t.Errorf("got: <%v>, want: <%v>", mp.Warnings, ErrUnsupportedVersion)
+	}
+
+	want := []Point{{X: -31.172, Y: 64.535}, {X: -27.913, Y: 70.218}}
+	if diff := cmp.Diff(mp.Trees, want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestNewWithOptions_strictVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "Newer version",
+			data: `{"features": [{"type": "Feature", "id": "values", "generator": "mfcg", "version": "0.9.1"}]}`,
+		},
+		{
+			name: "Unparseable version",
+			data: `{"features": [{"type": "Feature", "id": "values", "generator": "mfcg", "version": "latest"}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := New(strings.NewReader(test.data)); err != nil {
+				t.Errorf("got: <%v>, want error: <%v>", err, false)
+			}
+
+			_, err := NewWithOptions(strings.NewReader(test.data), Strict())
+			if !errors.Is(err, ErrUnsupportedVersion) {
+				t.Errorf("got: <%v>, want error: <%v>", err, ErrUnsupportedVersion)
+			}
+		})
+	}
+}
+
+func TestNew_otherGenerator(t *testing.T) {
+	data := `{"features": [
+		{"type": "Feature", "id": "values", "generator": "watabou", "version": "2.1"},
+		{"type": "MultiPoint", "id": "trees", "coordinates": [[1, 2]]}
+	]}`
+
+	mp, err := New(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("got: <%v>, want error: <%v>", err, false)
+	}
+
+	if len(mp.Warnings) != 1 || !errors.Is(mp.Warnings[0], ErrUnknownGenerator) {
+		t.Errorf("got: <%v>, want: <%v>", mp.Warnings, ErrUnknownGenerator)
+	}
+
+	if diff := cmp.Diff(mp.Trees, []Point{{X: 1, Y: 2}}); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestNew_olderVersion(t *testing.T) {
+	mp := testMap(t, testFileOld)
+
+	if diff := cmp.Diff(len(mp.Greens), 1); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(len(mp.Trees), 2); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+
+	if len(mp.Warnings) != 0 {
+		t.Errorf("got: <%v>, want: <%v>", mp.Warnings, nil)
+	}
+}
+
+func Test_decodeLenient(t *testing.T) {
+	f, err := os.Open(testFileFuture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	mp, err := decode(f, config{lenient: true})
+	if err != nil {
+		t.Fatalf("got: <%v>, want error: <%v>", err, false)
+	}
+
+	if len(mp.Warnings) != 1 || !errors.Is(mp.Warnings[0], ErrUnsupportedVersion) {
+		t.Errorf("got: <%v>, want: <%v>", mp.Warnings, ErrUnsupportedVersion)
+	}
+
+	want := []Point{{X: -31.172, Y: 64.535}, {X: -27.913, Y: 70.218}}
+	if diff := cmp.Diff(mp.Trees, want); diff != "" {
+		t.Errorf("mismatch (-got +want):\n%s", diff)
+	}
+}
@@ -0,0 +1,377 @@
+package mfcg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+)
+
+// vttFormat is the version of the Universal VTT format written by
+// WriteUniversalVTT.
+const vttFormat = 0.3
+
+// VTTOptions describes how a Map is exported to a virtual tabletop.
+type VTTOptions struct {
+	PixelsPerUnit float64 // PixelsPerUnit is the scale of the rendered image in pixels per map unit. Defaults to 8.
+	PixelsPerGrid int     // PixelsPerGrid is the size of a grid square in pixels. Defaults to 16.
+	Style         *Style  // Style is used to render the image. A nil Style is replaced by DefaultStyle.
+}
+
+// DefaultVTTOptions returns VTTOptions rendering the Map with DefaultStyle
+// onto a grid whose squares span two map units.
+func DefaultVTTOptions() *VTTOptions {
+	return &VTTOptions{PixelsPerUnit: 8, PixelsPerGrid: 16}
+}
+
+// vttFile is a Universal VTT map as written by Dungeondraft. Positions are
+// in grid squares from the top left corner of the image.
+type vttFile struct {
+	Format             float64        `json:"format"`
+	Resolution         vttResolution  `json:"resolution"`
+	LineOfSight        [][]vttPoint   `json:"line_of_sight"`
+	ObjectsLineOfSight [][]vttPoint   `json:"objects_line_of_sight"`
+	Portals            []vttPortal    `json:"portals"`
+	Environment        vttEnvironment `json:"environment"`
+	Lights             []struct{}     `json:"lights"`
+	Image              string         `json:"image"`
+}
+
+// vttResolution describes the grid of a vttFile.
+type vttResolution struct {
+	MapOrigin     vttPoint `json:"map_origin"`
+	MapSize       vttPoint `json:"map_size"`
+	PixelsPerGrid int      `json:"pixels_per_grid"`
+}
+
+// vttPoint is a position in grid squares.
+type vttPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// vttPortal is a door or opening in the line of sight.
+type vttPortal struct {
+	Position     vttPoint    `json:"position"`
+	Bounds       [2]vttPoint `json:"bounds"`
+	Rotation     float64     `json:"rotation"`
+	Closed       bool        `json:"closed"`
+	Freestanding bool        `json:"freestanding"`
+}
+
+// vttEnvironment describes the lighting of a vttFile.
+type vttEnvironment struct {
+	BakedLighting bool   `json:"baked_lighting"`
+	AmbientLight  string `json:"ambient_light"`
+}
+
+// WriteUniversalVTT writes the Map to w as a Universal VTT (.dd2vtt) file
+// using the provided VTTOptions. A nil VTTOptions is replaced by
+// DefaultVTTOptions. The file embeds the Map rendered as a PNG image sized to
+// a whole number of grid squares. Building outlines and walls block the line
+// of sight, and every gate where a road crosses a wall is written as an open
+// portal.
+func (m *Map) WriteUniversalVTT(w io.Writer, opts *VTTOptions) error {
+	g := newVTTGrid(m, opts)
+
+	var img bytes.Buffer
+	if err := png.Encode(&img, m.rasterize(g.style, g.origin, g.scale, g.width, g.height)); err != nil {
+		return err
+	}
+
+	f := vttFile{
+		Format: vttFormat,
+		Resolution: vttResolution{
+			MapSize:       vttPoint{X: float64(g.width / g.pixelsPerGrid), Y: float64(g.height / g.pixelsPerGrid)},
+			PixelsPerGrid: g.pixelsPerGrid,
+		},
+		LineOfSight:        [][]vttPoint{},
+		ObjectsLineOfSight: [][]vttPoint{},
+		Portals:            []vttPortal{},
+		Environment:        vttEnvironment{BakedLighting: true, AmbientLight: "ffffffff"},
+		Lights:             []struct{}{},
+		Image:              base64.StdEncoding.EncodeToString(img.Bytes()),
+	}
+
+	walls, gates := m.wallLines()
+	for _, line := range append(m.buildingLines(), walls...) {
+		pts := make([]vttPoint, len(line))
+		for i, pt := range line {
+			pts[i] = g.point(pt)
+		}
+		f.LineOfSight = append(f.LineOfSight, pts)
+	}
+	for _, gt := range gates {
+		a, b := g.point(gt.a), g.point(gt.b)
+		f.Portals = append(f.Portals, vttPortal{
+			Position: g.point(gt.center()),
+			Bounds:   [2]vttPoint{a, b},
+			Rotation: math.Atan2(b.Y-a.Y, b.X-a.X),
+		})
+	}
+
+	return json.NewEncoder(w).Encode(f)
+}
+
+// vttGrid maps a Map onto the image and grid of a virtual tabletop.
+type vttGrid struct {
+	style         *Style
+	origin        Point
+	scale         float64
+	width         int
+	height        int
+	pixelsPerGrid int
+}
+
+// newVTTGrid returns the grid spanning the extent of the Map at the scale
+// of the provided VTTOptions. The image is sized to a whole number of grid
+// squares, at least one in each direction.
+func newVTTGrid(m *Map, opts *VTTOptions) vttGrid {
+	def := DefaultVTTOptions()
+	if opts == nil {
+		opts = def
+	}
+
+	g := vttGrid{
+		style:         opts.Style,
+		origin:        m.extent().Min,
+		scale:         opts.PixelsPerUnit,
+		pixelsPerGrid: opts.PixelsPerGrid,
+	}
+	if g.scale <= 0 {
+		g.scale = def.PixelsPerUnit
+	}
+	if g.pixelsPerGrid <= 0 {
+		g.pixelsPerGrid = def.PixelsPerGrid
+	}
+
+	squares := func(length float64) int {
+		return int(math.Max(1, math.Ceil(length*g.scale/float64(g.pixelsPerGrid))))
+	}
+	r := m.extent()
+	g.width = squares(r.Dx()) * g.pixelsPerGrid
+	g.height = squares(r.Dy()) * g.pixelsPerGrid
+
+	return g
+}
+
+// point returns the position of Point pt in grid squares.
+func (g vttGrid) point(pt Point) vttPoint {
+	k := g.scale / float64(g.pixelsPerGrid)
+	return vttPoint{X: (pt.X - g.origin.X) * k, Y: (pt.Y - g.origin.Y) * k}
+}
+
+// gate is an opening in a wall where a road passes through it.
+type gate struct {
+	a, b Point // a and b are the ends of the opening along the wall.
+}
+
+// center returns the middle of the gate.
+func (g gate) center() Point {
+	return Point{X: (g.a.X + g.b.X) / 2, Y: (g.a.Y + g.b.Y) / 2}
+}
+
+// buildingLines returns the closed outline of every ring of the Map's
+// buildings.
+func (m *Map) buildingLines() [][]Point {
+	var lines [][]Point
+	for _, p := range m.Buildings {
+		for _, ring := range p.Coords {
+			if len(ring) >= 2 {
+				lines = append(lines, closeRing(ring))
+			}
+		}
+	}
+
+	return lines
+}
+
+// maxGateStretch is the most a gate is widened, relative to its road, where
+// the road crosses the wall at a shallow angle.
+const maxGateStretch = 4
+
+// wallLines returns the Map's walls as lines broken by gates, along with the
+// gates. A gate is opened wherever a road crosses a wall and spans the width
+// of the road along the wall.
+func (m *Map) wallLines() ([][]Point, []gate) {
+	var lines [][]Point
+	var gates []gate
+	for _, p := range m.Walls {
+		for _, ring := range p.Coords {
+			l, g := m.breakWall(m.wallPath(ring))
+			lines = append(lines, l...)
+			gates = append(gates, g...)
+		}
+	}
+
+	return lines, gates
+}
+
+// breakWall returns the wall line pts broken into lines by the gates opened
+// by the Map's roads, along with the gates. Gates are found along the whole
+// line, so a gate may span one of its vertices. A gate of a closed line may
+// also span its first Point, so the lines start where a gate ends.
+func (m *Map) breakWall(pts []Point) ([][]Point, []gate) {
+	pts, _ = removeDuplicates(append([]Point(nil), pts...))
+	if len(pts) < 2 {
+		return nil, nil
+	}
+
+	// Positions along the wall are given by their distance from pts[0].
+	cum := make([]float64, len(pts))
+	for i := 1; i < len(pts); i++ {
+		cum[i] = cum[i-1] + distance(pts[i-1], pts[i])
+	}
+	total := cum[len(cum)-1]
+	closed := isClosed(pts)
+
+	// at returns the Point of the wall at position d.
+	at := func(d float64) Point {
+		if closed && d > total {
+			d -= total
+		}
+		i := sort.SearchFloat64s(cum, d)
+		switch i {
+		case 0:
+			return pts[0]
+		case len(cum):
+			return pts[len(pts)-1]
+		}
+		s := segment{a: pts[i-1], b: pts[i]}
+		return s.at((d - cum[i-1]) / (cum[i] - cum[i-1]))
+	}
+
+	// line returns the stretch of wall between positions from and to, which
+	// goes past the first Point of a closed wall if to exceeds total.
+	line := func(from, to float64) []Point {
+		l := []Point{at(from)}
+		add := func(pt Point) {
+			if pt != l[len(l)-1] {
+				l = append(l, pt)
+			}
+		}
+		for lap := 0.0; lap*total < to; lap++ {
+			for i, c := range cum {
+				if c += lap * total; from < c && c < to {
+					add(pts[i])
+				}
+			}
+		}
+		add(at(to))
+
+		return l
+	}
+
+	gaps := m.gaps(pts, cum, closed)
+	if len(gaps) == 0 {
+		return [][]Point{pts}, nil
+	}
+
+	var lines [][]Point
+	var gates []gate
+	for k, g := range gaps {
+		gates = append(gates, gate{a: at(g[0]), b: at(g[1])})
+
+		from, to := g[1], total
+		switch {
+		case k+1 < len(gaps):
+			to = gaps[k+1][0]
+		case closed:
+			to = gaps[0][0] + total
+		}
+		if k == 0 && !closed && g[0] > 0 {
+			lines = append(lines, line(0, g[0]))
+		}
+		if to > from {
+			lines = append(lines, line(from, to))
+		}
+	}
+
+	return lines, gates
+}
+
+// gaps returns the stretches of the wall line pts crossed by the Map's
+// roads, as positions along the line sorted and merged where they overlap.
+// cum holds the position of each Point of pts. Each gap spans the width of
+// the road crossing it, measured along the wall. The gaps of a closed line
+// start before its end, but one of them may end past it and wrap around.
+func (m *Map) gaps(pts []Point, cum []float64, closed bool) [][2]float64 {
+	total := cum[len(cum)-1]
+
+	var gaps [][2]float64
+	for _, ln := range m.Roads {
+		half := ln.Width / 2
+		if half <= 0 {
+			half = float64(m.RoadWidth) / 2
+		}
+
+		for i := 1; i < len(pts); i++ {
+			s := segment{a: pts[i-1], b: pts[i]}
+			length := cum[i] - cum[i-1]
+			for j := 1; j < len(ln.Coords); j++ {
+				r := segment{a: ln.Coords[j-1], b: ln.Coords[j]}
+				// Roads passing through a vertex of the wall cross at the
+				// ends of its segments, which intersectSegments leaves out.
+				ts, _ := intersectSegments(s, r, 0)
+				if segmentDistance(s.a, r.a, r.b) == 0 {
+					ts = append(ts, 0)
+				}
+				if segmentDistance(s.b, r.a, r.b) == 0 {
+					ts = append(ts, 1)
+				}
+				if len(ts) == 0 {
+					continue
+				}
+
+				// A road crossing at an angle cuts a wider gap along the
+				// wall.
+				ux, uy := s.b.X-s.a.X, s.b.Y-s.a.Y
+				vx, vy := r.b.X-r.a.X, r.b.Y-r.a.Y
+				sin := math.Abs(ux*vy-uy*vx) / (length * math.Hypot(vx, vy))
+				w := half * math.Min(1/sin, maxGateStretch)
+
+				for _, t := range ts {
+					d := cum[i-1] + t*length
+					gaps = append(gaps, [2]float64{d - w, d + w})
+				}
+			}
+		}
+	}
+
+	for i, g := range gaps {
+		if !closed {
+			gaps[i] = [2]float64{math.Max(0, g[0]), math.Min(total, g[1])}
+			continue
+		}
+		start := math.Mod(g[0], total)
+		if start < 0 {
+			start += total
+		}
+		gaps[i] = [2]float64{start, start + math.Min(total, g[1]-g[0])}
+	}
+
+	sort.Slice(gaps, func(i, j int) bool {
+		return gaps[i][0] < gaps[j][0]
+	})
+
+	var merged [][2]float64
+	for _, g := range gaps {
+		if n := len(merged); n > 0 && g[0] <= merged[n-1][1] {
+			merged[n-1][1] = math.Max(merged[n-1][1], g[1])
+			continue
+		}
+		merged = append(merged, g)
+	}
+
+	// A gap wrapping around a closed line may reach the first gaps.
+	for closed && len(merged) > 1 && merged[0][0]+total <= merged[len(merged)-1][1] {
+		last := &merged[len(merged)-1]
+		last[1] = math.Max(last[1], merged[0][1]+total)
+		merged = merged[1:]
+	}
+
+	return merged
+}
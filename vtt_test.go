@@ -0,0 +1,154 @@
+package mfcg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestMap_WriteUniversalVTT(t *testing.T) {
+	wall := Polygon{Coords: [][]Point{{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 100}, {X: 0, Y: 100}}}}
+	road := LineString{Width: 6, Coords: []Point{{X: 50, Y: -20}, {X: 50, Y: 50}}}
+
+	tests := []struct {
+		name       string
+		mp         *Map
+		opts       *VTTOptions
+		wantSize   vttPoint
+		wantSight  [][]vttPoint
+		wantPortal []vttPortal
+	}{
+		{
+			name: "Gate",
+			mp: &Map{
+				Buildings: []Polygon{square(20, 20, 10)},
+				Roads:     []LineString{road},
+				Walls:     []Polygon{wall},
+			},
+			opts:     &VTTOptions{PixelsPerUnit: 1, PixelsPerGrid: 10},
+			wantSize: vttPoint{X: 11, Y: 13},
+			wantSight: [][]vttPoint{
+				{{X: 2.3, Y: 4.3}, {X: 3.3, Y: 4.3}, {X: 3.3, Y: 5.3}, {X: 2.3, Y: 5.3}, {X: 2.3, Y: 4.3}},
+				{{X: 5.6, Y: 2.3}, {X: 10.3, Y: 2.3}, {X: 10.3, Y: 12.3}, {X: 0.3, Y: 12.3}, {X: 0.3, Y: 2.3}, {X: 5, Y: 2.3}},
+			},
+			wantPortal: []vttPortal{{
+				Position: vttPoint{X: 5.3, Y: 2.3},
+				Bounds:   [2]vttPoint{{X: 5, Y: 2.3}, {X: 5.6, Y: 2.3}},
+			}},
+		},
+		{
+			name: "Oblique gate",
+			mp: &Map{
+				Roads: []LineString{{Width: 6, Coords: []Point{{X: 30, Y: -20}, {X: 70, Y: 20}}}},
+				Walls: []Polygon{wall},
+			},
+			opts:     &VTTOptions{PixelsPerUnit: 1, PixelsPerGrid: 10},
+			wantSize: vttPoint{X: 11, Y: 13},
+			wantSight: [][]vttPoint{
+				{{X: 5.3 + 0.3*math.Sqrt2, Y: 2.3}, {X: 10.3, Y: 2.3}, {X: 10.3, Y: 12.3}, {X: 0.3, Y: 12.3}, {X: 0.3, Y: 2.3}, {X: 5.3 - 0.3*math.Sqrt2, Y: 2.3}},
+			},
+			wantPortal: []vttPortal{{
+				Position: vttPoint{X: 5.3, Y: 2.3},
+				Bounds:   [2]vttPoint{{X: 5.3 - 0.3*math.Sqrt2, Y: 2.3}, {X: 5.3 + 0.3*math.Sqrt2, Y: 2.3}},
+			}},
+		},
+		{
+			name: "Gate at a corner",
+			mp: &Map{
+				Roads: []LineString{{Width: 6, Coords: []Point{{X: -20, Y: -20}, {X: 20, Y: 20}}}},
+				Walls: []Polygon{wall},
+			},
+			opts:     &VTTOptions{PixelsPerUnit: 1, PixelsPerGrid: 10},
+			wantSize: vttPoint{X: 13, Y: 13},
+			wantSight: [][]vttPoint{
+				{{X: 2.3 + 0.3*math.Sqrt2, Y: 2.3}, {X: 12.3, Y: 2.3}, {X: 12.3, Y: 12.3}, {X: 2.3, Y: 12.3}, {X: 2.3, Y: 2.3 + 0.3*math.Sqrt2}},
+			},
+			wantPortal: []vttPortal{{
+				Position: vttPoint{X: 2.3 + 0.15*math.Sqrt2, Y: 2.3 + 0.15*math.Sqrt2},
+				Bounds:   [2]vttPoint{{X: 2.3, Y: 2.3 + 0.3*math.Sqrt2}, {X: 2.3 + 0.3*math.Sqrt2, Y: 2.3}},
+				Rotation: -math.Pi / 4,
+			}},
+		},
+		{
+			name: "No roads",
+			mp: &Map{
+				Walls: []Polygon{wall},
+			},
+			opts:     &VTTOptions{PixelsPerUnit: 0.5, PixelsPerGrid: 20},
+			wantSize: vttPoint{X: 3, Y: 3},
+			wantSight: [][]vttPoint{
+				{{X: 0, Y: 0}, {X: 2.5, Y: 0}, {X: 2.5, Y: 2.5}, {X: 0, Y: 2.5}, {X: 0, Y: 0}},
+			},
+			wantPortal: []vttPortal{},
+		},
+		{
+			name: "Open wall",
+			mp: &Map{
+				Walls:     []Polygon{wall},
+				OpenWalls: true,
+			},
+			opts:     &VTTOptions{PixelsPerUnit: 0.5, PixelsPerGrid: 20},
+			wantSize: vttPoint{X: 3, Y: 3},
+			wantSight: [][]vttPoint{
+				{{X: 0, Y: 0}, {X: 2.5, Y: 0}, {X: 2.5, Y: 2.5}, {X: 0, Y: 2.5}},
+			},
+			wantPortal: []vttPortal{},
+		},
+		{
+			name:       "Empty map",
+			mp:         &Map{},
+			opts:       nil,
+			wantSize:   vttPoint{X: 1, Y: 1},
+			wantSight:  [][]vttPoint{},
+			wantPortal: []vttPortal{},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := test.mp.WriteUniversalVTT(&buf, test.opts); err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+
+			var got vttFile
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+
+			approx := cmpopts.EquateApprox(0, 1e-9)
+			if diff := cmp.Diff(got.Resolution.MapSize, test.wantSize); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+			if diff := cmp.Diff(got.LineOfSight, test.wantSight, approx); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+			if diff := cmp.Diff(got.Portals, test.wantPortal, approx); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+
+			data, err := base64.StdEncoding.DecodeString(got.Image)
+			if err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+			cfg, err := png.DecodeConfig(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("got: <%v>, want error: <%v>", err, false)
+			}
+			size := vttPoint{
+				X: float64(cfg.Width / got.Resolution.PixelsPerGrid),
+				Y: float64(cfg.Height / got.Resolution.PixelsPerGrid),
+			}
+			if diff := cmp.Diff(size, test.wantSize); diff != "" {
+				t.Errorf("mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}